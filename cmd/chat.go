@@ -0,0 +1,232 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"openai-realtime/pkg/audiomanager"
+	"openai-realtime/pkg/openai"
+	"openai-realtime/pkg/openai/events"
+	"openai-realtime/pkg/persona"
+	"openai-realtime/pkg/usage"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	chatModalitiesText  = "text"       // 텍스트 입력, 텍스트 출력
+	chatModalitiesAudio = "text,audio" // 텍스트 입력, 음성(+전사) 출력
+
+	chatResponseTimeout = 2 * time.Minute // 응답을 기다리는 최대 시간
+)
+
+// chat 서브커맨드: 입력한 줄을 사용자 메시지로 보내고 응답을 출력합니다.
+// --text 모드에서는 마이크를 사용하지 않으며, 텍스트 출력만 사용하면 PortAudio 도 초기화하지 않습니다.
+func runChat(args []string) {
+	flags := flag.NewFlagSet("chat", flag.ExitOnError)
+	textInput := flags.Bool("text", false, "type user messages instead of speaking")
+	personaName := flags.String("persona", persona.DefaultName, "persona profile to use (e.g. tutor, doctor, friend)")
+	personaDir := flags.String("persona-dir", "", "directory with persona JSON files that override the built-in ones")
+	summaryFile := flags.String("summary-file", "", "file with a summary of the previous session, available to persona templates as {{.summary}}")
+	vars := variablesFlag{}
+	flags.Var(vars, "var", "persona template variable as name=value (repeatable)")
+	usageFile := flags.String("usage-file", "", "export token usage and estimated cost on exit (.csv appends, .json overwrites)")
+	pricesFile := flags.String("prices", "", "JSON price table in USD per 1M tokens (default: built-in prices for the connected model)")
+	softBudget := flags.String("soft-budget", "", "session budget that degrades responses when reached, in tokens (20000) or dollars ($0.50)")
+	hardBudget := flags.String("hard-budget", "", "session budget that ends the session when reached, in tokens (40000) or dollars ($1)")
+	degradeMode := flags.String("budget-degrade", budgetDegradeShort, "how to degrade at the soft budget: short (shorter responses) or text (text-only responses)")
+	wrapUp := flags.Bool("budget-wrap-up", true, "ask the assistant to wrap up the conversation at the soft budget")
+	metricsAddr := flags.String("metrics-addr", "", "serve Prometheus metrics on this address (e.g. localhost:9090), disabled if empty")
+	modalities := flags.String("modalities", chatModalitiesText, "response modalities: text (text only) or text,audio (text in, audio out)")
+	_ = flags.Parse(args)
+
+	if !*textInput {
+		fmt.Fprintln(os.Stderr, "chat: only --text input is supported")
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	var responseModalities []string // 세션 modalities
+	switch *modalities {
+	case chatModalitiesText:
+		responseModalities = []string{"text"}
+	case chatModalitiesAudio:
+		responseModalities = []string{"text", "audio"}
+	default:
+		fmt.Fprintf(os.Stderr, "chat: unknown modalities %q (want %q or %q)\n", *modalities, chatModalitiesText, chatModalitiesAudio)
+		os.Exit(2)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	openAI := createOpenAIClient(ctx)
+	defer openAI.Close()
+	openai.SubscribeConsole(openAI) // 응답 텍스트 스트리밍 출력
+	collector := startMetrics(ctx, *metricsAddr)
+	if collector != nil {
+		openAI.SetMetrics(collector)
+	}
+
+	toolRegistry := createToolRegistry()
+	toolRegistry.Attach(openAI) // 함수 호출 처리
+
+	// 음성 입력이 없으므로 턴 감지는 사용하지 않음
+	p := loadPersona(*personaName, *personaDir, vars, *summaryFile)
+	opts := append(personaSessionOptions(p, toolRegistry),
+		openai.WithModalities(responseModalities...),
+		openai.WithoutTurnDetection(),
+	)
+	if err := openAI.SessionUpdate(opts...); err != nil {
+		log.Fatalf("Failed to update session: %v", err)
+	}
+
+	// 응답별 토큰 사용량과 예상 비용 기록, 예산 초과 시 응답을 줄이거나 세션 종료
+	var player *audioPlayer // --modalities text,audio 에서만 사용
+	prices := loadPrices(*pricesFile, openAI.Model())
+	ledger := usage.NewLedger(prices)
+	ledger.Attach(openAI, p.Name)
+	if guard := newBudgetGuard(*softBudget, *hardBudget, *degradeMode, *wrapUp, prices); guard != nil {
+		guard.Attach(openAI, func(used usage.Summary, last events.Response) {
+			go endSessionAfterPlayback(openAI, player, last, cancel)
+		})
+	}
+
+	if *modalities == chatModalitiesAudio {
+		// 음성 출력에만 오디오 장치를 사용
+		initializePortAudio()
+		defer shutdownPortAudio()
+
+		audioManager, err := audiomanager.NewManager(nil, audiomanager.SelectOutputDevice(), 10) // 출력 전용
+		if err != nil {
+			log.Fatalf("Failed to create audio manager: %v", err)
+		}
+		defer audioManager.Close()
+
+		player = &audioPlayer{am: audioManager, openAI: openAI}
+		if collector != nil {
+			audioManager.DeviceController.SetMetrics(collector)
+		}
+		go audioManager.Start(ctx)                       // 오디오 매니저 시작
+		go receiveAndSaveFromOpenAI(ctx, player, cancel) // OpenAI로부터 오디오를 받아 재생
+	}
+
+	// 응답이 끝나면 다음 입력을 받음 (함수 호출 응답은 도구 실행 후 이어지는 응답을 기다림)
+	responseDone := make(chan struct{}, 1)
+	openai.On(openAI, events.ResponseDoneEventType, func(ctx context.Context, e events.ResponseDone) {
+		for _, item := range e.Response.Output {
+			if item.Type == events.ItemTypeFunctionCall && e.Response.Status == events.ResponseStatusCompleted {
+				return
+			}
+		}
+		select {
+		case responseDone <- struct{}{}:
+		default:
+		}
+	})
+
+	// 첫 인사가 끝난 뒤 입력을 받음
+	greeting := p.Greeting != ""
+	if err := p.Greet(openAI); err != nil {
+		log.Errorf("Failed to send greeting: %v", err)
+		greeting = false
+	}
+
+	// 서버 오류로 응답이 오지 않으면 다음 입력을 받음
+	responseFailed := make(chan struct{}, 1)
+
+	go openAI.ReceiveServerEvent(ctx, cancel)                                     // openAI의 ServerEvent 를 수신 및 처리
+	go logReconnectEvents(ctx, openAI)                                            // 재연결 상태 로그 출력
+	go logServerErrors(ctx, openAI, responseFailed)                               // 세션을 유지하는 서버 오류 로그 출력
+	go handleInterruptSignal(ctx, cancel)                                         // 인터럽트 신호 수신 및 종료 신호 전달
+	go readChatInput(ctx, openAI, greeting, responseDone, responseFailed, cancel) // 입력한 줄을 메시지로 전송
+
+	<-ctx.Done()
+	if err := openAI.Wait(); err != nil {
+		log.Errorf("Session ended with error: %v", err)
+	}
+	reportUsage(ledger, *usageFile)
+	log.Info("Chat finished")
+}
+
+// 입력한 줄을 사용자 메시지 아이템으로 보내고 응답을 요청하는 함수 (/quit 또는 EOF 시 종료)
+func readChatInput(ctx context.Context, openAI *openai.Client, waitGreeting bool, responseDone, responseFailed <-chan struct{}, cancel context.CancelFunc) {
+	defer cancel()
+
+	if waitGreeting && !waitChatResponse(ctx, responseDone, responseFailed) {
+		return
+	}
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for {
+		fmt.Print("> ")
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				log.Errorf("Error reading input: %v", err)
+			}
+			return
+		}
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if line == "/quit" {
+			return
+		}
+
+		// 입력을 기다리는 동안 받은 알림은 이번 응답과 관계없음
+		drainSignals(responseDone, responseFailed)
+
+		if err := openAI.ConversationItemCreate(line, "user"); err != nil {
+			log.Errorf("Failed to send message: %v", err)
+			return
+		}
+		if err := openAI.ResponseCreate(nil); err != nil {
+			var limitErr *openai.RateLimitError
+			switch {
+			case errors.As(err, &limitErr):
+				log.Warnf("Response not requested, try again later: %v", err)
+				continue
+			case errors.Is(err, openai.ErrResponsesClosed):
+				return // 예산 초과로 세션 종료 중
+			}
+			log.Errorf("Failed to request response: %v", err)
+			return
+		}
+
+		if !waitChatResponse(ctx, responseDone, responseFailed) {
+			return
+		}
+	}
+}
+
+// 응답이 끝나거나, 서버 오류로 응답이 오지 않거나, 시간이 초과될 때까지 기다리는 함수
+// 세션이 끝나면 false 를 반환합니다.
+func waitChatResponse(ctx context.Context, responseDone, responseFailed <-chan struct{}) bool {
+	timeout := time.NewTimer(chatResponseTimeout)
+	defer timeout.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-responseDone:
+	case <-responseFailed:
+	case <-timeout.C:
+		log.Warnf("No response within %s", chatResponseTimeout)
+	}
+	return true
+}
+
+// 대기 중인 알림을 버리는 함수
+func drainSignals(signals ...<-chan struct{}) {
+	for _, signal := range signals {
+		select {
+		case <-signal:
+		default:
+		}
+	}
+}
@@ -3,15 +3,25 @@ package main
 import (
 	"bufio"
 	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"github.com/gordonklaus/portaudio"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
 	"openai-realtime/pkg/audiomanager"
 	"openai-realtime/pkg/audioutils"
 	"openai-realtime/pkg/config"
+	"openai-realtime/pkg/metrics"
 	"openai-realtime/pkg/openai"
 	"openai-realtime/pkg/openai/events"
+	"openai-realtime/pkg/persona"
+	"openai-realtime/pkg/tools"
+	"openai-realtime/pkg/usage"
 	"os"
 	"os/signal"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -29,6 +39,33 @@ var (
 	rxFileName            = "rx.pcm"
 	txWavFileName         = "tx.wav"
 	rxWavFileName         = "rx.wav"
+
+	personaName = flag.String("persona", persona.DefaultName, "persona profile to use (e.g. tutor, doctor, friend)")
+	personaDir  = flag.String("persona-dir", "", "directory with persona JSON files that override the built-in ones")
+	summaryFile = flag.String("summary-file", "", "file with a summary of the previous session, available to persona templates as {{.summary}}")
+	personaVars = variablesFlag{}
+	usageFile   = flag.String("usage-file", "", "export token usage and estimated cost on exit (.csv appends, .json overwrites)")
+	pricesFile  = flag.String("prices", "", "JSON price table in USD per 1M tokens (default: built-in prices for the connected model)")
+	softBudget  = flag.String("soft-budget", "", "session budget that degrades responses when reached, in tokens (20000) or dollars ($0.50)")
+	hardBudget  = flag.String("hard-budget", "", "session budget that ends the session when reached, in tokens (40000) or dollars ($1)")
+	degradeMode = flag.String("budget-degrade", budgetDegradeShort, "how to degrade at the soft budget: short (shorter responses) or text (text-only responses)")
+	wrapUp      = flag.Bool("budget-wrap-up", true, "ask the assistant to wrap up the conversation at the soft budget")
+	metricsAddr = flag.String("metrics-addr", "", "serve Prometheus metrics on this address (e.g. localhost:9090), disabled if empty")
+	turnMode    = flag.String("turn", turnModeServer, "turn detection mode: server (server VAD), local (client-side endpoint detection) or ptt (push-to-talk)")
+)
+
+const (
+	turnModeServer = "server" // 서버 VAD 가 턴을 관리
+	turnModeLocal  = "local"  // 클라이언트 끝점 검출로 commit 및 response.create 전송
+	turnModePTT    = "ptt"    // Enter 키로 말하기 시작/종료 (push-to-talk)
+)
+
+const (
+	budgetDegradeShort = "short" // 소프트 예산 도달 시 응답 길이 제한
+	budgetDegradeText  = "text"  // 소프트 예산 도달 시 텍스트로만 응답
+
+	playbackDrainTimeout = 15 * time.Second // 하드 예산 도달 후 마지막 응답 재생을 기다리는 최대 시간
+	playbackStallTimeout = time.Second      // 재생 위치가 이 시간 동안 멈춰 있으면 재생이 중단된 것으로 봄
 )
 
 func initializePortAudio() {
@@ -53,6 +90,86 @@ func createOpenAIClient(ctx context.Context) *openai.Client {
 	return client
 }
 
+// 현재 시간 도구 인자
+type currentTimeArgs struct {
+	Timezone string `json:"timezone,omitempty" description:"IANA timezone name, e.g. Asia/Seoul"`
+}
+
+// 모델이 호출할 수 있는 도구를 등록하는 함수
+func createToolRegistry() *tools.Registry {
+	registry := tools.NewRegistry(5 * time.Second)
+
+	err := tools.Register(registry, "get_current_time", "Returns the current local date and time.",
+		func(ctx context.Context, args currentTimeArgs) (interface{}, error) {
+			location := time.Local
+			if args.Timezone != "" {
+				loc, err := time.LoadLocation(args.Timezone)
+				if err != nil {
+					return nil, err
+				}
+				location = loc
+			}
+			return map[string]string{"time": time.Now().In(location).Format(time.RFC3339)}, nil
+		})
+	if err != nil {
+		log.Fatalf("Failed to register tool: %v", err)
+	}
+	return registry
+}
+
+// --var name=value 형태로 여러 번 받을 수 있는 템플릿 변수 플래그
+type variablesFlag persona.Variables
+
+func (v variablesFlag) String() string {
+	pairs := make([]string, 0, len(v))
+	for name, value := range v {
+		pairs = append(pairs, name+"="+value)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (v variablesFlag) Set(s string) error {
+	name, value, ok := strings.Cut(s, "=")
+	if !ok || name == "" {
+		return fmt.Errorf("expected name=value, got %q", s)
+	}
+	v[name] = value
+	return nil
+}
+
+// 페르소나를 불러와 세션 시작 시점의 변수로 프롬프트를 렌더링하는 함수
+// summaryFile 이 주어지면 이전 세션 요약으로 사용합니다.
+func loadPersona(name, dir string, vars variablesFlag, summaryFile string) *persona.Persona {
+	p, err := persona.Load(name, dir)
+	if err != nil {
+		log.Fatalf("Failed to load persona: %v", err)
+	}
+
+	if summaryFile != "" {
+		summary, err := os.ReadFile(summaryFile)
+		if err != nil {
+			log.Fatalf("Failed to read summary file: %v", err)
+		}
+		vars[persona.VarSummary] = strings.TrimSpace(string(summary))
+	}
+
+	rendered, err := p.Render(persona.Variables(vars), time.Now())
+	if err != nil {
+		log.Fatalf("Failed to render persona: %v", err)
+	}
+	log.Infof("Using persona %s", rendered.Name)
+	return rendered
+}
+
+// 페르소나 설정과 페르소나가 사용하는 도구로 세션 옵션을 만드는 함수
+func personaSessionOptions(p *persona.Persona, registry *tools.Registry) []openai.SessionOption {
+	personaTools, err := registry.ToolsNamed(p.Tools)
+	if err != nil {
+		log.Fatalf("Persona %s: %v", p.Name, err)
+	}
+	return append(p.SessionOptions(), openai.WithTools(personaTools))
+}
+
 // Enter 키를 누르면 종료 신호를 보내는 함수
 func waitForUserExitSignal(ctx context.Context, cancel context.CancelFunc) {
 	defer func() {
@@ -106,12 +223,224 @@ func handleInterruptSignal(ctx context.Context, cancel context.CancelFunc) {
 	}
 }
 
-func listenAndSendToOpenAI(ctx context.Context, am *audiomanager.Manager, openAI *openai.Client, cancel context.CancelFunc) {
+// 재연결 상태를 로그로 출력하는 함수
+func logReconnectEvents(ctx context.Context, openAI *openai.Client) {
+	defer func() {
+		log.Debug("Log reconnect events stopped")
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-openAI.ReconnectChan:
+			switch event.State {
+			case openai.ReconnectStateReconnecting:
+				log.Warnf("Connection lost, reconnecting (attempt %d)", event.Attempt)
+			case openai.ReconnectStateReconnected:
+				log.Infof("Reconnected after %d attempt(s)", event.Attempt)
+			case openai.ReconnectStateFailed:
+				log.Errorf("Reconnect failed: %v", event.Err)
+			}
+		}
+	}
+}
+
+// 가격표 읽기 (model 의 기본 가격에 파일 내용을 덮어씀)
+func loadPrices(pricesFile, model string) usage.Prices {
+	prices, err := usage.PricesFor(model)
+	if pricesFile == "" {
+		if err != nil {
+			log.Fatalf("Failed to load prices: %v (use -prices)", err)
+		}
+		return prices
+	}
+	prices, err = usage.LoadPrices(pricesFile, prices)
+	if err != nil {
+		log.Fatalf("Failed to load prices: %v", err)
+	}
+	return prices
+}
+
+// 종료 시 사용량 요약 출력 및 파일로 내보내기
+func reportUsage(ledger *usage.Ledger, usageFile string) {
+	ledger.PrintSummary(os.Stdout)
+	if usageFile == "" {
+		return
+	}
+	if err := ledger.Export(usageFile); err != nil {
+		log.Errorf("Failed to export usage: %v", err)
+		return
+	}
+	log.Infof("Usage exported to %s", usageFile)
+}
+
+// 예산 플래그로 예산 감시자 생성 (예산이 없으면 nil)
+func newBudgetGuard(soft, hard, degrade string, wrapUp bool, prices usage.Prices) *usage.Guard {
+	budget := usage.Budget{}
+	var err error
+	if budget.Soft, err = usage.ParseLimit(soft); err != nil {
+		log.Fatalf("Invalid soft budget: %v", err)
+	}
+	if budget.Hard, err = usage.ParseLimit(hard); err != nil {
+		log.Fatalf("Invalid hard budget: %v", err)
+	}
+	if budget.Soft.IsZero() && budget.Hard.IsZero() {
+		return nil
+	}
+
+	switch degrade {
+	case budgetDegradeShort:
+		budget.SoftOptions = usage.ShortResponseOptions
+	case budgetDegradeText:
+		budget.SoftOptions = usage.TextOnlyOptions
+	default:
+		log.Fatalf("Unknown budget degrade mode %q (want %q or %q)", degrade, budgetDegradeShort, budgetDegradeText)
+	}
+	if wrapUp {
+		budget.WrapUp = usage.DefaultWrapUp
+	}
+
+	log.Infof("Session budget: soft %s, hard %s", budget.Soft, budget.Hard)
+	return usage.NewGuard(budget, prices)
+}
+
+// 하드 예산 도달 시 새 응답을 막고, 마지막 응답의 재생이 끝나면 종료 신호 전달
+func endSessionAfterPlayback(openAI *openai.Client, player *audioPlayer, last events.Response, cancel context.CancelFunc) {
+	defer cancel()
+
+	// 클라이언트 끝점 검출, push-to-talk, 도구 후속 응답이 새 응답을 요청하지 않도록 막고
+	// 서버 VAD 가 새 응답을 만들지 않도록 턴 감지를 끔
+	openAI.CloseResponses()
+	if err := openAI.AmendSession(openai.WithoutTurnDetection()); err != nil {
+		log.Errorf("Failed to disable turn detection: %v", err)
+	}
+	if player == nil {
+		return
+	}
+
+	// 아직 재생을 시작하지 않은 음성도 끝까지 재생되기를 기다림 (barge-in 등으로 재생이 멈추면 중단)
+	deadline := time.Now().Add(playbackDrainTimeout)
+	for _, output := range last.Output {
+		lastPlayedMs, lastProgress := -1, time.Now()
+		for time.Now().Before(deadline) {
+			item, ok := openAI.Conversation().Item(output.ID)
+			if !ok || item.AudioDurationMs == 0 {
+				break // 음성이 없는 아이템
+			}
+			playedMs, started := player.PlayedMs(output.ID)
+			if started && playedMs >= item.AudioDurationMs {
+				break
+			}
+			if playedMs != lastPlayedMs {
+				lastPlayedMs, lastProgress = playedMs, time.Now()
+			} else if started && time.Since(lastProgress) > playbackStallTimeout {
+				break
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+}
+
+// /metrics 제공 시작 (addr 가 비어 있으면 nil)
+func startMetrics(ctx context.Context, addr string) *metrics.Realtime {
+	if addr == "" {
+		return nil
+	}
+	registry := prometheus.NewRegistry()
+	collector := metrics.NewRealtime(registry)
+	go func() {
+		if err := metrics.Serve(ctx, addr, registry); err != nil {
+			log.Errorf("Metrics server stopped: %v", err)
+		}
+	}()
+	return collector
+}
+
+// logServerErrors 세션을 유지하는 서버 오류를 로그로 출력합니다.
+// failed 가 nil 이 아니면 오류마다 알립니다. (가득 차 있으면 버림)
+func logServerErrors(ctx context.Context, openAI *openai.Client, failed chan<- struct{}) {
+	defer func() {
+		log.Debug("Log server errors stopped")
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case err := <-openAI.ErrChan:
+			var apiErr *openai.APIError
+			if errors.As(err, &apiErr) && apiErr.Retryable() {
+				log.Warnf("Temporary server error, try again later: %v", err)
+			} else {
+				log.Warnf("Request rejected by server: %v", err)
+			}
+			if failed != nil {
+				select {
+				case failed <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}
+}
+
+// 턴 모드에 맞는 turn_detection 설정 (nil 이면 서버 VAD 사용 안 함)
+func turnDetectionFor(mode string) *events.TurnDetection {
+	switch mode {
+	case turnModeServer:
+		return openai.DefaultTurnDetection()
+	case turnModeLocal, turnModePTT:
+		return nil
+	default:
+		log.Fatalf("Unknown turn mode: %s", mode)
+		return nil
+	}
+}
+
+// 클라이언트 끝점 검출 결과에 따라 턴을 관리하는 함수 (local 모드)
+// 발화가 끝나면 입력 버퍼를 commit 하고 응답을 요청합니다.
+func handleLocalEndpoint(event audiomanager.EndpointEvent, openAI *openai.Client, bargeIn *openai.BargeIn, latency *openai.LatencyTracker) error {
+	switch event {
+	case audiomanager.EndpointSpeechStarted:
+		log.Debug("Local VAD: speech started")
+		bargeIn.Interrupt()
+	case audiomanager.EndpointNoise:
+		log.Debug("Local VAD: noise discarded")
+		return openAI.SendInputAudioBufferClear()
+	case audiomanager.EndpointSpeechStopped:
+		log.Debug("Local VAD: speech stopped, committing input audio")
+		latency.SpeechStopped(time.Now())
+		if err := openAI.SendInputAudioBufferCommit(); err != nil {
+			return err
+		}
+		// 한도 때문에 기다릴 수 있으므로 마이크 입력 처리를 막지 않습니다.
+		go func() {
+			if err := openAI.ResponseCreate(nil); err != nil && !errors.Is(err, openai.ErrResponsesClosed) {
+				log.Errorf("Failed to request response: %v", err)
+			}
+		}()
+	}
+	return nil
+}
+
+func listenAndSendToOpenAI(ctx context.Context, am *audiomanager.Manager, openAI *openai.Client, bargeIn *openai.BargeIn, ptt *pushToTalk, latency *openai.LatencyTracker, cancel context.CancelFunc) {
 	defer func() {
 		log.Debug("Audio processing to OpenAI stopped")
 	}()
 	log.Info("Starting audio processing to OpenAI")
 
+	var detector *audiomanager.EndpointDetector
+	if *turnMode == turnModeLocal {
+		detector = audiomanager.NewEndpointDetector(audiomanager.EndpointConfig{
+			RmsThresholdDb: config.RmsThresholdDb,
+			UseZCR:         config.UseZCR,
+			ZcrThreshold:   config.ZcrThreshold,
+			MinSpeechMs:    config.MinSpeechMs,
+			HangoverMs:     config.HangoverMs,
+		}, am.DeviceController.SampleRate)
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -129,7 +458,22 @@ func listenAndSendToOpenAI(ctx context.Context, am *audiomanager.Manager, openAI
 			useZCR := config.UseZCR
 			zcrThreshold := config.ZcrThreshold
 
-			if audiomanager.IsSilentAudioDataEx(audioData, rmsThresholdDb, useZCR, zcrThreshold) {
+			var endpoint audiomanager.EndpointEvent
+			if ptt != nil {
+				// ptt 모드: 키가 눌린 동안에만 무음을 포함해 모두 전송
+				if !ptt.Talking() {
+					continue
+				}
+			} else if detector != nil {
+				// local 모드: 발화 중에는 말 사이의 무음도 함께 전송
+				endpoint = detector.Process(audioData)
+				if !detector.Active() && endpoint != audiomanager.EndpointSpeechStopped {
+					if err := handleLocalEndpoint(endpoint, openAI, bargeIn, latency); err != nil {
+						log.Errorf("Failed to handle local endpoint: %v", err)
+					}
+					continue
+				}
+			} else if audiomanager.IsSilentAudioDataEx(audioData, rmsThresholdDb, useZCR, zcrThreshold) {
 				log.Debug("Silent audio data detected, skipping transmission")
 				continue
 			}
@@ -140,7 +484,7 @@ func listenAndSendToOpenAI(ctx context.Context, am *audiomanager.Manager, openAI
 				log.Error("Resampling failed, skipping this chunk")
 				continue
 			}
-			log.Debug("Resampled audio data from %d Hz to 24000 Hz", am.DeviceController.SampleRate)
+			log.Debugf("Resampled audio data from %d Hz to 24000 Hz", am.DeviceController.SampleRate)
 
 			// Convert to byte array
 			byteAudioData := audioutils.ConvertToByteArrayLE(resampled)
@@ -148,11 +492,22 @@ func listenAndSendToOpenAI(ctx context.Context, am *audiomanager.Manager, openAI
 
 			// Send audio data to OpenAI
 			if err := openAI.SendInputAudioBufferAppend(byteAudioData); err != nil {
+				if errors.Is(err, openai.ErrSendQueueFull) {
+					log.Warnf("Send queue is full, discarding audio data: %v", err)
+					continue
+				}
 				log.Errorf("Failed to send audio to OpenAI: %v", err)
 				cancel() // Cancel context on error
 				return
 			}
 
+			if ptt != nil {
+				ptt.AddSent(len(audioData) * 1000 / am.DeviceController.SampleRate)
+			}
+			if err := handleLocalEndpoint(endpoint, openAI, bargeIn, latency); err != nil {
+				log.Errorf("Failed to handle local endpoint: %v", err)
+			}
+
 			// Append PCM data to file in a separate goroutine
 			go func(data []byte) {
 				if err := openai.AppendPCMDataToFile(txFileName, data); err != nil {
@@ -163,25 +518,59 @@ func listenAndSendToOpenAI(ctx context.Context, am *audiomanager.Manager, openAI
 		}
 	}
 }
-func receiveAndSaveFromOpenAI(ctx context.Context, am *audiomanager.Manager, openAI *openai.Client, cancel context.CancelFunc) {
+
+// OpenAI 음성을 재생 장치로 전달하는 구조체 (barge-in 시 StopPlayback 으로 중단)
+type audioPlayer struct {
+	am     *audiomanager.Manager
+	openAI *openai.Client
+
+	mu sync.Mutex // StopPlayback 중에 새 음성이 OutputChan 으로 들어가지 않도록 보호
+}
+
+// StopPlayback 재생 대기 중인 음성을 모두 버립니다.
+func (p *audioPlayer) StopPlayback() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// 아직 변환되지 않은 OpenAI 음성
+	for drained := false; !drained; {
+		select {
+		case <-p.openAI.AudioOutputChan:
+		default:
+			drained = true
+		}
+	}
+
+	// 장치 샘플레이트로 변환되어 대기 중인 음성
+	dropped := p.am.DeviceController.Flush()
+	log.Debugf("Playback stopped, %d samples dropped", dropped)
+}
+
+// PlayedMs itemID 아이템이 스피커에서 재생된 길이
+func (p *audioPlayer) PlayedMs(itemID string) (int, bool) {
+	position, ok := p.am.DeviceController.PlaybackPosition(itemID)
+	return position.PlayedMs, ok
+}
+
+func receiveAndSaveFromOpenAI(ctx context.Context, player *audioPlayer, cancel context.CancelFunc) {
 	defer func() {
 		log.Debug("Receive and save from OpenAI stopped")
 	}()
 	log.Info("Starting to receive and save from OpenAI")
 
-	framesPerBuffer := am.DeviceController.SampleRate / 10 // e.g., 44100 / 10 = 4410
-	buffer := make([]int16, 0, framesPerBuffer*10)         // Initialize buffer with sufficient capacity
+	am, openAI := player.am, player.openAI
 
 	for {
 		select {
 		case <-ctx.Done():
 			log.Info("Context done, stopping receive and save from OpenAI")
 			return
-		case audioData, ok := <-openAI.AudioOutputChan:
+		case audioChunk, ok := <-openAI.AudioOutputChan:
 			if !ok {
 				log.Info("Audio channel closed, stopping receive and save from OpenAI")
 				return
 			}
+			audioData := audioChunk.Data
 
 			log.Debugf("Received audio data of length: %d bytes", len(audioData))
 
@@ -200,22 +589,22 @@ func receiveAndSaveFromOpenAI(ctx context.Context, am *audiomanager.Manager, ope
 			}
 			log.Debugf("Resampled audio length: %d samples", len(resampled))
 
-			// Append resampled data to the buffer
-			buffer = append(buffer, resampled...)
-
-			// Send fixed-size chunks to OutputChan for playback
-			for len(buffer) >= framesPerBuffer {
-				chunk := buffer[:framesPerBuffer]
-				buffer = buffer[framesPerBuffer:]
-
-				select {
-				case <-ctx.Done():
-					log.Info("Context done while sending to OutputChan")
-					return
-				case am.DeviceController.OutputChan <- chunk:
-					log.Debug("Sending buffer of length: %d samples to OutputChan", len(chunk))
-				}
+			// Send chunk tagged with its response item to OutputChan for playback
+			player.mu.Lock()
+			select {
+			case <-ctx.Done():
+				player.mu.Unlock()
+				log.Info("Context done while sending to OutputChan")
+				return
+			case am.DeviceController.OutputChan <- audiomanager.OutputChunk{
+				ResponseID:   audioChunk.ResponseID,
+				ItemID:       audioChunk.ItemID,
+				ContentIndex: audioChunk.ContentIndex,
+				Samples:      resampled,
+			}:
+				log.Debugf("Sending buffer of length: %d samples to OutputChan", len(resampled))
 			}
+			player.mu.Unlock()
 
 			// Append PCM data to file in a separate goroutine
 			go func(data []byte) {
@@ -228,7 +617,18 @@ func receiveAndSaveFromOpenAI(ctx context.Context, am *audiomanager.Manager, ope
 	}
 }
 
+func init() {
+	flag.Var(personaVars, "var", "persona template variable as name=value (repeatable), e.g. --var user_name=민준 --var age=9")
+}
+
 func main() {
+	// chat 서브커맨드 (텍스트 입력)
+	if len(os.Args) > 1 && os.Args[1] == "chat" {
+		runChat(os.Args[2:])
+		return
+	}
+
+	flag.Parse()
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -250,20 +650,37 @@ func main() {
 	// OpenAI 클라이언트 생성
 	openAI := createOpenAIClient(ctx)
 	defer openAI.Close()
+	openai.SubscribeConsole(openAI) // 응답 텍스트/전사 콘솔 출력
+	openAI.OnStateChange(func(change openai.StateChange) {
+		log.Debugf("State: %s -> %s (%s)", change.From, change.To, change.Reason)
+	})
+	if collector := startMetrics(ctx, *metricsAddr); collector != nil {
+		openAI.SetMetrics(collector)
+		audioManager.DeviceController.SetMetrics(collector)
+	}
 
 	// OpenAI 에 Project 전송
-	iat := events.InputAudioTranscription{
-		Model: "whisper-1",
+	toolRegistry := createToolRegistry()
+	toolRegistry.Attach(openAI) // 함수 호출 처리
+
+	// 서버 VAD 모드에서만 페르소나의 턴 감지 설정을 사용
+	p := loadPersona(*personaName, *personaDir, personaVars, *summaryFile)
+	opts := []openai.SessionOption{
+		openai.WithInputAudioTranscription("whisper-1"),
+		openai.WithTurnDetection(turnDetectionFor(*turnMode)),
+	}
+	opts = append(opts, personaSessionOptions(p, toolRegistry)...)
+	if *turnMode != turnModeServer {
+		opts = append(opts, openai.WithoutTurnDetection())
 	}
 
-	tDetection := events.TurnDetection{
-		Type:              "server_vad",
-		Threshold:         0.5,
-		PrefixPaddingMs:   300,
-		SilenceDurationMs: 500,
+	if err := openAI.SessionUpdate(opts...); err != nil {
+		log.Fatalf("Failed to update session: %v", err)
 	}
 
-	openAI.SessionUpdate(iat, tDetection, []events.Tool{})
+	if err := p.Greet(openAI); err != nil {
+		log.Errorf("Failed to send greeting: %v", err)
+	}
 
 	// 파일 명 업데이트 (날짜_파일명)
 	datetime := time.Now().Format("20060102_150405")
@@ -272,18 +689,56 @@ func main() {
 	txWavFileName = datetime + "_" + txWavFileName
 	rxWavFileName = datetime + "_" + rxWavFileName
 
-	// ReceiveServerEvent goroutine
-	go openAI.ReceiveServerEvent(ctx, cancel)                      // openAI의 ServerEvent 를 수신 및 처리
-	go audioManager.Start(ctx)                                     // 오디오 매니저 시작
-	go listenAndSendToOpenAI(ctx, audioManager, openAI, cancel)    // 오디오 장치로부터 오디오를 받아 OpenAI로 전송
-	go receiveAndSaveFromOpenAI(ctx, audioManager, openAI, cancel) // OpenAI로부터 오디오를 받아 재생
+	// 사용자가 말을 시작하면 어시스턴트 음성을 중단 (barge-in)
+	player := &audioPlayer{am: audioManager, openAI: openAI}
+
+	// 응답별 토큰 사용량과 예상 비용 기록, 예산 초과 시 응답을 줄이거나 세션 종료
+	prices := loadPrices(*pricesFile, openAI.Model())
+	ledger := usage.NewLedger(prices)
+	ledger.Attach(openAI, p.Name)
+	if guard := newBudgetGuard(*softBudget, *hardBudget, *degradeMode, *wrapUp, prices); guard != nil {
+		guard.Attach(openAI, func(used usage.Summary, last events.Response) {
+			go endSessionAfterPlayback(openAI, player, last, cancel)
+		})
+	}
+	bargeIn := openai.EnableBargeIn(openAI, player)
+
+	// 사용자가 말을 멈춘 뒤 어시스턴트 음성이 들리기까지의 턴 지연 시간 측정
+	latency := openAI.Latency()
+	latency.WaitForPlayback()
+	audioManager.DeviceController.OnPlaybackStarted(func(position audiomanager.PlaybackPosition) {
+		latency.PlaybackStarted(position.ItemID, position.StartedAt)
+	})
+
+	// push-to-talk 모드에서는 키가 눌린 동안에만 음성을 전송
+	var ptt *pushToTalk
+	if *turnMode == turnModePTT {
+		ptt = &pushToTalk{openAI: openAI, bargeIn: bargeIn}
+	}
 
-	go waitForUserExitSignal(ctx, cancel) // 사용자 입력을 대기 및 종료 신호 전달
+	// ReceiveServerEvent goroutine
+	go openAI.ReceiveServerEvent(ctx, cancel)                                          // openAI의 ServerEvent 를 수신 및 처리
+	go logReconnectEvents(ctx, openAI)                                                 // 재연결 상태 로그 출력
+	go logServerErrors(ctx, openAI, nil)                                               // 세션을 유지하는 서버 오류 로그 출력
+	go audioManager.Start(ctx)                                                         // 오디오 매니저 시작
+	go listenAndSendToOpenAI(ctx, audioManager, openAI, bargeIn, ptt, latency, cancel) // 오디오 장치로부터 오디오를 받아 OpenAI로 전송
+	go receiveAndSaveFromOpenAI(ctx, player, cancel)                                   // OpenAI로부터 오디오를 받아 재생
+
+	if ptt != nil {
+		go handlePushToTalkKeys(ctx, ptt, cancel) // Enter 키로 말하기 전환, q 입력 시 종료 신호 전달
+	} else {
+		go waitForUserExitSignal(ctx, cancel) // 사용자 입력을 대기 및 종료 신호 전달
+	}
 	go handleInterruptSignal(ctx, cancel) // 인터럽트 신호 수신 및 종료 신호 전달
 
 	// 종료 신호를 대기
 	<-ctx.Done()
 	log.Info("Context done in main function")
+	if err := openAI.Wait(); err != nil {
+		log.Errorf("Session ended with error: %v", err)
+	}
+	reportUsage(ledger, *usageFile)
+	latency.LogSummary()
 
 	// convert pcm to wav by ffmpeg
 
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"openai-realtime/pkg/openai"
+	"os"
+	"strings"
+	"sync"
+)
+
+// 이보다 짧은 입력은 서버가 commit 을 거부하므로 버립니다.
+const minCommitAudioMs = 100
+
+// push-to-talk 상태 (ptt 모드)
+// 키가 눌린 동안에만 마이크 음성을 전송하고, 놓으면 입력 버퍼를 commit 하고 응답을 요청합니다.
+type pushToTalk struct {
+	openAI  *openai.Client
+	bargeIn *openai.BargeIn
+
+	mu      sync.Mutex
+	talking bool
+	sentMs  int // 이번 턴에 전송한 음성 길이
+}
+
+// Talking 키가 눌려 음성을 전송 중인지 여부
+func (p *pushToTalk) Talking() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.talking
+}
+
+// AddSent 전송한 음성 길이를 기록합니다.
+func (p *pushToTalk) AddSent(ms int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sentMs += ms
+}
+
+// Start 말하기 시작: 재생 중인 응답을 중단하고 입력 버퍼를 비웁니다.
+// 전송 중에는 잠금을 잡지 않으며, 버퍼를 비운 뒤부터 마이크 음성을 보냅니다. (Start/Stop 은 키 입력 goroutine 에서만 호출)
+func (p *pushToTalk) Start() error {
+	if p.Talking() {
+		return nil
+	}
+	p.bargeIn.Interrupt()
+	if err := p.openAI.SendInputAudioBufferClear(); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	p.talking = true
+	p.sentMs = 0
+	p.mu.Unlock()
+	log.Info("Push-to-talk: listening (press Enter to send)")
+	return nil
+}
+
+// Stop 말하기 종료: 입력 버퍼를 commit 하고 응답을 요청합니다.
+// 응답 요청은 한도 때문에 기다릴 수 있으므로 상태만 바꾼 뒤 잠금을 풀고 전송합니다.
+func (p *pushToTalk) Stop() error {
+	p.mu.Lock()
+	if !p.talking {
+		p.mu.Unlock()
+		return nil
+	}
+	p.talking = false
+	sentMs := p.sentMs
+	p.mu.Unlock()
+
+	if sentMs < minCommitAudioMs {
+		log.Info("Push-to-talk: too short, discarded")
+		return p.openAI.SendInputAudioBufferClear()
+	}
+
+	log.Info("Push-to-talk: sent")
+	if err := p.openAI.SendInputAudioBufferCommit(); err != nil {
+		return err
+	}
+	return p.openAI.ResponseCreate(nil)
+}
+
+// Toggle 말하기 상태를 전환합니다.
+func (p *pushToTalk) Toggle() error {
+	if p.Talking() {
+		return p.Stop()
+	}
+	return p.Start()
+}
+
+// Enter 키로 push-to-talk 을 전환하고, q 를 입력하면 종료 신호를 보내는 함수
+func handlePushToTalkKeys(ctx context.Context, ptt *pushToTalk, cancel context.CancelFunc) {
+	defer func() {
+		log.Debug("Push-to-talk key handling stopped")
+	}()
+	log.Info("Push-to-talk: press Enter to talk, Enter again to send, q + Enter to quit")
+
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		if err := scanner.Err(); err != nil {
+			log.Errorf("Error reading input: %v", err)
+		}
+		close(lines)
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case line, ok := <-lines:
+			if !ok {
+				return
+			}
+			if strings.TrimSpace(line) == "q" {
+				cancel()
+				log.Info("Context cancelled by user key press")
+				return
+			}
+			if err := ptt.Toggle(); err != nil {
+				log.Errorf("Push-to-talk failed: %v", err)
+			}
+		}
+	}
+}
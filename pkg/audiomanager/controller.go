@@ -6,6 +6,8 @@ import (
 	"github.com/gordonklaus/portaudio"
 	"openai-realtime/pkg/audioutils"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // Recorder는 오디오를 녹음하는 구조체입니다.
@@ -15,27 +17,67 @@ type Controller struct {
 	SampleRate   int
 	VolumeThresh float32
 
-	InputChan  chan []int16 // 마이크로부터 오디오 데이터 채널
-	OutputChan chan []int16 // 스피커로 출력할 오디오 데이터 채널
-	ErrorChan  chan error   // 오류 채널
+	InputChan  chan []int16     // 마이크로부터 오디오 데이터 채널
+	OutputChan chan OutputChunk // 스피커로 출력할 오디오 데이터 채널 (chunk 5개 버퍼, 가득 차면 재생 콜백이 꺼낼 때까지 보내는 쪽이 블록됨)
+	ErrorChan  chan error       // 오류 채널
 
 	stream   *portaudio.Stream // 포트오디오 스트림
 	stopOnce sync.Once         // Off() 메서드가 한 번만 실행되도록 보장
+
+	outputMu sync.Mutex       // process 와 Flush 사이의 출력 상태 보호
+	current  *OutputChunk     // 재생 중인 chunk (offset 이후가 남은 샘플)
+	offset   int              // current 에서 다음에 재생할 샘플 위치
+	playback *playbackTracker // 아이템별 재생 위치
+
+	metrics atomic.Value // metricsHolder (SetMetrics 참고)
+	started atomic.Value // func(PlaybackPosition) (OnPlaybackStarted 참고)
 }
 
+const fadeOutMs = 30 // Flush 시 페이드 아웃 길이
+
 // NewController 생성자 함수
+// inputDevice 가 nil 이면 출력 장치만 열며, 샘플레이트는 출력 장치 기준입니다. (InputChan 으로는 아무것도 보내지 않음)
 func NewController(inputDevice *portaudio.DeviceInfo, outputDevice *portaudio.DeviceInfo, volumeThreshold float32) *Controller {
+	sampleRate := outputDevice.DefaultSampleRate
+	if inputDevice != nil {
+		sampleRate = inputDevice.DefaultSampleRate
+	}
 	return &Controller{
 		InputDevice:  inputDevice,
 		OutputDevice: outputDevice,
-		SampleRate:   int(inputDevice.DefaultSampleRate),
+		SampleRate:   int(sampleRate),
 		VolumeThresh: volumeThreshold,
-		InputChan:    make(chan []int16, 5), // 버퍼링하여 블로킹 방지
-		OutputChan:   make(chan []int16, 5), // 버퍼링하여 블로킹 방지
+		InputChan:    make(chan []int16, 5),     // 버퍼링하여 블로킹 방지
+		OutputChan:   make(chan OutputChunk, 5), // 버퍼링하여 블로킹 방지
 		ErrorChan:    make(chan error, 1),
+		playback:     newPlaybackTracker(int(sampleRate)),
+	}
+}
+
+// PlaybackPosition itemID 아이템이 현재까지 재생된 위치를 반환합니다.
+// 출력 장치에 전달된 샘플 중 출력 지연(OutputLatency)이 지나 실제로 스피커에서 재생된 부분만 PlayedMs 에 포함됩니다.
+func (c *Controller) PlaybackPosition(itemID string) (PlaybackPosition, bool) {
+	return c.playback.position(itemID, time.Now())
+}
+
+// OnPlaybackStarted 아이템의 첫 샘플이 출력 장치에 전달될 때마다 호출될 함수를 등록합니다. (nil 이면 해제)
+// position.StartedAt 은 출력 지연을 반영한 실제 재생 시작 시각입니다.
+// 오디오 콜백에서 호출되므로 빠르게 반환해야 합니다.
+func (c *Controller) OnPlaybackStarted(fn func(position PlaybackPosition)) {
+	c.started.Store(fn)
+}
+
+func (c *Controller) playbackStarted(position PlaybackPosition) {
+	if fn, ok := c.started.Load().(func(PlaybackPosition)); ok && fn != nil {
+		fn(position)
 	}
 }
 
+// OutputLatency 출력 장치의 지연 시간 (스트림이 열리기 전에는 0)
+func (c *Controller) OutputLatency() time.Duration {
+	return c.playback.outputLatency()
+}
+
 // Off 녹음을 중지하고 스트림을 종료합니다.
 func (c *Controller) Off() {
 	c.stopOnce.Do(func() {
@@ -60,6 +102,10 @@ func (c *Controller) On(ctx context.Context) error {
 		return fmt.Errorf("failed to open stream: %w", err)
 	}
 	c.stream = stream
+	if info := stream.Info(); info != nil {
+		c.playback.setLatency(info.OutputLatency)
+		log.Debugf("Output latency: %v", info.OutputLatency)
+	}
 	defer func() {
 		if err := stream.Close(); err != nil {
 			log.Warnf("Failed to close stream: %v", err)
@@ -93,19 +139,11 @@ func (c *Controller) On(ctx context.Context) error {
 }
 
 func (c *Controller) getStreamParam() portaudio.StreamParameters {
-	if c.InputDevice == nil {
-		log.Fatal("Input device is not set")
-	}
-
 	if c.OutputDevice == nil {
 		log.Fatal("Output device is not set")
 	}
 
-	return portaudio.StreamParameters{
-		Input: portaudio.StreamDeviceParameters{
-			Device:   c.InputDevice,
-			Channels: 1,
-		},
+	params := portaudio.StreamParameters{
 		Output: portaudio.StreamDeviceParameters{
 			Device:   c.OutputDevice,
 			Channels: 1, // mono 출력
@@ -114,25 +152,101 @@ func (c *Controller) getStreamParam() portaudio.StreamParameters {
 		SampleRate:      float64(c.SampleRate),
 		FramesPerBuffer: c.SampleRate / 10, // 0.1초 단위 버퍼
 	}
+	if c.InputDevice != nil { // 출력 전용이면 마이크를 열지 않음
+		params.Input = portaudio.StreamDeviceParameters{
+			Device:   c.InputDevice,
+			Channels: 1,
+		}
+	}
+	return params
 }
 
 func (c *Controller) process(in []int16, out []int16) {
-	// 입력 처리
-	inputCopy := make([]int16, len(in))
-	copy(inputCopy, in)
-	select {
-	case c.InputChan <- inputCopy:
-	default:
-		log.Warn("Input channel is full, discarding audio data")
+	// 입력 처리 (출력 전용이면 in 이 비어 있음)
+	if len(in) > 0 {
+		inputCopy := make([]int16, len(in))
+		copy(inputCopy, in)
+		select {
+		case c.InputChan <- inputCopy:
+		default:
+			log.Warn("Input channel is full, discarding audio data")
+			c.metricsHook().InputDropped(c.samplesDuration(len(inputCopy)))
+		}
 	}
 
 	// 출력 처리
-	select {
-	case data := <-c.OutputChan:
-		audioutils.CopyAudioData(out, data)
-	default:
-		for i := range out {
-			out[i] = 0
+	c.outputMu.Lock()
+	defer c.outputMu.Unlock()
+
+	now := time.Now()
+	filled := 0
+	for filled < len(out) {
+		if c.current == nil || c.offset >= len(c.current.Samples) {
+			select {
+			case chunk := <-c.OutputChan:
+				c.current, c.offset = &chunk, 0
+				continue
+			default:
+			}
+			break
 		}
+
+		n := copy(out[filled:], c.current.Samples[c.offset:])
+		if position, started := c.playback.written(c.current, n, now.Add(c.samplesDuration(filled))); started {
+			c.playbackStarted(position)
+		}
+		c.offset += n
+		filled += n
+	}
+	audioutils.CopyAudioData(out[filled:], nil) // 남은 구간은 무음
+}
+
+// samplesDuration 샘플 수를 재생 시간으로 변환합니다.
+func (c *Controller) samplesDuration(samples int) time.Duration {
+	return time.Duration(samples) * time.Second / time.Duration(c.SampleRate)
+}
+
+// Flush 재생 대기 중인 오디오를 모두 버리고, 다음 버퍼에서 짧게 페이드 아웃합니다.
+// 재생되지 못하고 버려진 샘플 수를 반환합니다. (페이드 아웃 구간은 재생된 것으로 간주)
+func (c *Controller) Flush() (droppedSamples int) {
+	c.outputMu.Lock()
+	defer c.outputMu.Unlock()
+	defer func() {
+		c.metricsHook().PlaybackFlushed(c.samplesDuration(droppedSamples))
+	}()
+
+	var fade *OutputChunk
+	if c.current != nil && c.offset < len(c.current.Samples) {
+		remaining := *c.current
+		remaining.Samples = c.current.Samples[c.offset:]
+		fade = &remaining
+	}
+
+	for {
+		select {
+		case chunk := <-c.OutputChan:
+			if fade == nil {
+				fade = &chunk
+			} else {
+				droppedSamples += len(chunk.Samples)
+			}
+			continue
+		default:
+		}
+		break
+	}
+
+	c.current, c.offset = nil, 0
+	if fade == nil {
+		return droppedSamples
+	}
+
+	fadeSamples := c.SampleRate * fadeOutMs / 1000
+	if fadeSamples > len(fade.Samples) {
+		fadeSamples = len(fade.Samples)
 	}
+	droppedSamples += len(fade.Samples) - fadeSamples
+	fade.Samples = audioutils.FadeOut(fade.Samples[:fadeSamples])
+	c.current = fade
+	return droppedSamples
 }
@@ -0,0 +1,88 @@
+package audiomanager
+
+// EndpointEvent 끝점 검출 결과
+type EndpointEvent int
+
+const (
+	EndpointNone          EndpointEvent = iota
+	EndpointSpeechStarted               // 최소 발화 길이 이상 말하기 시작함
+	EndpointSpeechStopped               // hangover 동안 무음이 이어져 발화가 끝남
+	EndpointNoise                       // 최소 발화 길이에 못 미치고 끝난 소리 (잡음)
+)
+
+// EndpointConfig 클라이언트 측 끝점 검출 설정
+type EndpointConfig struct {
+	RmsThresholdDb float64 // IsSilentAudioDataEx 참고
+	UseZCR         bool
+	ZcrThreshold   float64
+
+	MinSpeechMs int // 이 길이 이상 소리가 이어져야 발화로 인정
+	HangoverMs  int // 발화 중 이 길이 이상 무음이 이어지면 발화 종료
+}
+
+// EndpointDetector IsSilentAudioDataEx 로 판정한 프레임 단위 음성 여부에
+// 최소 발화 길이와 hangover 를 적용하여 사용자가 말을 시작하고 끝낸 시점을 검출합니다.
+type EndpointDetector struct {
+	config     EndpointConfig
+	sampleRate int
+
+	inSpeech  bool
+	speechMs  int // 연속된 소리 길이 (발화 인정 전)
+	silenceMs int // 발화 중 연속된 무음 길이
+}
+
+// NewEndpointDetector 생성자 함수
+func NewEndpointDetector(config EndpointConfig, sampleRate int) *EndpointDetector {
+	return &EndpointDetector{
+		config:     config,
+		sampleRate: sampleRate,
+	}
+}
+
+// Process 프레임 하나를 처리하고 상태가 바뀌면 해당 이벤트를 반환합니다.
+func (d *EndpointDetector) Process(data []int16) EndpointEvent {
+	frameMs := len(data) * 1000 / d.sampleRate
+	silent := IsSilentAudioDataEx(data, d.config.RmsThresholdDb, d.config.UseZCR, d.config.ZcrThreshold)
+
+	if d.inSpeech {
+		if !silent {
+			d.silenceMs = 0
+			return EndpointNone
+		}
+
+		d.silenceMs += frameMs
+		if d.silenceMs < d.config.HangoverMs {
+			return EndpointNone
+		}
+		d.Reset()
+		return EndpointSpeechStopped
+	}
+
+	if silent {
+		if d.speechMs == 0 {
+			return EndpointNone
+		}
+		d.speechMs = 0
+		return EndpointNoise
+	}
+
+	d.speechMs += frameMs
+	if d.speechMs < d.config.MinSpeechMs {
+		return EndpointNone
+	}
+	d.inSpeech = true
+	d.speechMs = 0
+	return EndpointSpeechStarted
+}
+
+// Active 발화 중이거나 발화 여부를 판단 중인 소리가 이어지고 있는지 여부
+func (d *EndpointDetector) Active() bool {
+	return d.inSpeech || d.speechMs > 0
+}
+
+// Reset 상태를 초기화합니다.
+func (d *EndpointDetector) Reset() {
+	d.inSpeech = false
+	d.speechMs = 0
+	d.silenceMs = 0
+}
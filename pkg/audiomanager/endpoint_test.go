@@ -0,0 +1,54 @@
+package audiomanager
+
+import "testing"
+
+// testFrame 10ms (16kHz) 프레임, loud 이면 큰 진폭의 사각파
+func testFrame(loud bool) []int16 {
+	frame := make([]int16, 160)
+	if loud {
+		for i := range frame {
+			frame[i] = 8000
+			if i%2 == 1 {
+				frame[i] = -8000
+			}
+		}
+	}
+	return frame
+}
+
+func TestEndpointDetector(t *testing.T) {
+	d := NewEndpointDetector(EndpointConfig{RmsThresholdDb: -40, MinSpeechMs: 30, HangoverMs: 50}, 16000)
+
+	feed := func(loud bool, frames int) []EndpointEvent {
+		var got []EndpointEvent
+		for i := 0; i < frames; i++ {
+			if e := d.Process(testFrame(loud)); e != EndpointNone {
+				got = append(got, e)
+			}
+		}
+		return got
+	}
+
+	if got := feed(true, 2); len(got) != 0 || !d.Active() {
+		t.Fatalf("short sound: events %v, active %v, want none and active", got, d.Active())
+	}
+	if got := feed(false, 1); len(got) != 1 || got[0] != EndpointNoise {
+		t.Fatalf("short sound then silence: events %v, want [noise]", got)
+	}
+
+	if got := feed(true, 5); len(got) != 1 || got[0] != EndpointSpeechStarted {
+		t.Fatalf("speech: events %v, want [speech started]", got)
+	}
+	if got := feed(false, 4); len(got) != 0 {
+		t.Fatalf("pause shorter than hangover: events %v, want none", got)
+	}
+	if got := feed(true, 1); len(got) != 0 {
+		t.Fatalf("speech resumed: events %v, want none", got)
+	}
+	if got := feed(false, 5); len(got) != 1 || got[0] != EndpointSpeechStopped {
+		t.Fatalf("silence for hangover: events %v, want [speech stopped]", got)
+	}
+	if d.Active() {
+		t.Error("detector still active after speech stopped")
+	}
+}
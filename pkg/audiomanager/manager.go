@@ -24,11 +24,12 @@ type Manager struct {
 	errorChan  chan error
 }
 
+// NewManager 생성자 함수 (inputDevice 가 nil 이면 마이크를 열지 않는 출력 전용)
 func NewManager(inputDevice *portaudio.DeviceInfo, outputDevice *portaudio.DeviceInfo, volumeThreshold float32) (*Manager, error) {
-
+	controller := NewController(inputDevice, outputDevice, volumeThreshold)
 	return &Manager{
-		DeviceController: NewController(inputDevice, outputDevice, volumeThreshold),
-		SampleRate:       int(inputDevice.DefaultSampleRate),
+		DeviceController: controller,
+		SampleRate:       controller.SampleRate,
 		VolumeThresh:     volumeThreshold,
 		errorChan:        make(chan error),
 	}, nil
@@ -0,0 +1,37 @@
+package audiomanager
+
+import "time"
+
+// Metrics 오디오 장치 계측 지점 (Controller.SetMetrics 로 등록)
+// 오디오 콜백에서 호출되므로 빠르게 반환해야 합니다.
+type Metrics interface {
+	InputDropped(d time.Duration)    // InputChan 이 가득 차 버린 마이크 입력 길이
+	PlaybackFlushed(d time.Duration) // Flush 로 재생되지 못하고 버린 출력 길이
+}
+
+// noopMetrics 계측을 사용하지 않을 때의 기본값
+type noopMetrics struct{}
+
+func (noopMetrics) InputDropped(time.Duration)    {}
+func (noopMetrics) PlaybackFlushed(time.Duration) {}
+
+// metricsHolder atomic.Value 에 서로 다른 구현을 저장하기 위한 상자
+type metricsHolder struct {
+	Metrics
+}
+
+// SetMetrics 계측 구현을 등록합니다. nil 이면 계측을 끕니다.
+func (c *Controller) SetMetrics(m Metrics) {
+	if m == nil {
+		m = noopMetrics{}
+	}
+	c.metrics.Store(metricsHolder{m})
+}
+
+// metricsHook 현재 계측 구현
+func (c *Controller) metricsHook() Metrics {
+	if holder, ok := c.metrics.Load().(metricsHolder); ok {
+		return holder.Metrics
+	}
+	return noopMetrics{}
+}
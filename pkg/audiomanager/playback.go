@@ -0,0 +1,152 @@
+package audiomanager
+
+import (
+	"sync"
+	"time"
+)
+
+// 위치를 유지할 최근 아이템 수
+const maxTrackedItems = 32
+
+// OutputChunk 스피커로 출력할 오디오와 그 출처 (응답, 아이템, content index)
+type OutputChunk struct {
+	ResponseID   string
+	ItemID       string
+	ContentIndex int
+	Samples      []int16
+}
+
+// PlaybackPosition 아이템별 재생 위치
+type PlaybackPosition struct {
+	ResponseID   string
+	ItemID       string
+	ContentIndex int
+
+	WrittenMs int       // 출력 장치에 전달된 길이
+	PlayedMs  int       // 실제로 스피커에서 재생된 길이 (출력 지연 반영)
+	StartedAt time.Time // 첫 샘플이 스피커에서 재생되기 시작한 시각 (예상)
+}
+
+// playbackSegment 한 번의 콜백에서 장치에 전달된 아이템 구간
+type playbackSegment struct {
+	at      time.Time // 스피커에서 재생이 시작되는 시각 (전달 시각 + 출력 지연)
+	startMs float64   // 아이템 내 시작 위치
+	length  float64   // 길이 (ms)
+}
+
+type itemPlayback struct {
+	position  PlaybackPosition
+	writtenMs float64
+	playedMs  float64 // 모두 재생되어 정리된 구간까지의 위치
+	segments  []playbackSegment
+}
+
+// playbackTracker 장치에 전달된 샘플을 아이템별로 기록하여 재생 위치를 계산합니다.
+type playbackTracker struct {
+	mu         sync.Mutex
+	sampleRate int
+	latency    time.Duration
+	items      map[string]*itemPlayback
+	order      []string
+}
+
+func newPlaybackTracker(sampleRate int) *playbackTracker {
+	return &playbackTracker{
+		sampleRate: sampleRate,
+		items:      make(map[string]*itemPlayback),
+	}
+}
+
+func (t *playbackTracker) setLatency(latency time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.latency = latency
+}
+
+func (t *playbackTracker) outputLatency() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.latency
+}
+
+// written chunk 의 samples 개 샘플이 now 에 장치로 전달되었음을 기록합니다.
+// 아이템의 첫 샘플이면 그 아이템의 재생 위치와 true 를 반환합니다.
+func (t *playbackTracker) written(chunk *OutputChunk, samples int, now time.Time) (started PlaybackPosition, ok bool) {
+	if chunk.ItemID == "" || samples <= 0 {
+		return PlaybackPosition{}, false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	item, found := t.items[chunk.ItemID]
+	if !found {
+		item = &itemPlayback{position: PlaybackPosition{
+			ResponseID:   chunk.ResponseID,
+			ItemID:       chunk.ItemID,
+			ContentIndex: chunk.ContentIndex,
+			StartedAt:    now.Add(t.latency),
+		}}
+		t.items[chunk.ItemID] = item
+		t.order = append(t.order, chunk.ItemID)
+		if len(t.order) > maxTrackedItems {
+			delete(t.items, t.order[0])
+			t.order = t.order[1:]
+		}
+	}
+
+	length := float64(samples) * 1000 / float64(t.sampleRate)
+	item.segments = append(item.segments, playbackSegment{
+		at:      now.Add(t.latency),
+		startMs: item.writtenMs,
+		length:  length,
+	})
+	item.writtenMs += length
+	item.prune(now)
+	return item.position, !found
+}
+
+// position itemID 의 now 시점 재생 위치
+func (t *playbackTracker) position(itemID string, now time.Time) (PlaybackPosition, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	item, ok := t.items[itemID]
+	if !ok {
+		return PlaybackPosition{}, false
+	}
+
+	position := item.position
+	position.WrittenMs = int(item.writtenMs)
+	position.PlayedMs = int(item.playedAt(now))
+	return position, true
+}
+
+// playedAt now 시점까지 스피커에서 재생된 길이 (ms)
+func (i *itemPlayback) playedAt(now time.Time) float64 {
+	played := i.playedMs
+	for _, segment := range i.segments {
+		elapsed := float64(now.Sub(segment.at)) / float64(time.Millisecond)
+		if elapsed <= 0 {
+			break
+		}
+		if elapsed > segment.length {
+			elapsed = segment.length
+		}
+		played = segment.startMs + elapsed
+	}
+	return played
+}
+
+// prune 모두 재생된 구간을 정리합니다.
+func (i *itemPlayback) prune(now time.Time) {
+	n := 0
+	for _, segment := range i.segments {
+		if now.Sub(segment.at) < time.Duration(segment.length*float64(time.Millisecond)) {
+			break
+		}
+		i.playedMs = segment.startMs + segment.length
+		n++
+	}
+	i.segments = i.segments[n:]
+}
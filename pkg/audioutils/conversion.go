@@ -62,6 +62,16 @@ func CopyAudioData(out, data []int16) {
 	}
 }
 
+// FadeOut 데이터의 복사본에 선형 페이드 아웃을 적용합니다.
+func FadeOut(data []int16) []int16 {
+	faded := make([]int16, len(data))
+	for i, sample := range data {
+		gain := float64(len(data)-i) / float64(len(data))
+		faded[i] = int16(float64(sample) * gain)
+	}
+	return faded
+}
+
 // fillSilence는 버퍼를 무음으로 채웁니다.
 func fillSilence(buffer []int16) {
 	for i := range buffer {
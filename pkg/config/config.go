@@ -2,7 +2,6 @@ package config
 
 import (
 	"github.com/sirupsen/logrus"
-	"os"
 )
 
 var (
@@ -10,12 +9,6 @@ var (
 	RmsThresholdDb = -50.0 // -50 dBFS 이하일 경우 무음으로 간주
 	UseZCR         = false
 	ZcrThreshold   = 0.15 // ZCR이 15% 이하일 경우 무음으로 간주
-	SystemPrompt   = func() string {
-		file, err := os.ReadFile("config/tutor_prompt.txt")
-		if err != nil {
-			return ""
-		}
-
-		return string(file)
-	}
+	MinSpeechMs    = 300  // 클라이언트 VAD: 이 길이 이상 소리가 이어져야 발화로 인정
+	HangoverMs     = 1000 // 클라이언트 VAD: 발화 후 이 길이 이상 무음이면 턴 종료 (아이들은 말 사이 쉼이 길다)
 )
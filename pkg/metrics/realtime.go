@@ -0,0 +1,174 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"openai-realtime/pkg/config"
+	"openai-realtime/pkg/openai"
+	"openai-realtime/pkg/openai/events"
+	"os"
+	"time"
+)
+
+var log = func() *logrus.Logger {
+	log := logrus.New()
+	log.SetOutput(os.Stdout)
+	log.SetLevel(config.LogLevel)
+	return log
+}()
+
+// 턴 지연 시간 히스토그램 구간 (초)
+var turnLatencyBuckets = []float64{0.25, 0.5, 0.75, 1, 1.5, 2, 3, 5, 10}
+
+// Realtime openai.Client 와 audiomanager.Controller 의 계측 구현
+type Realtime struct {
+	eventsSent     *prometheus.CounterVec
+	eventsReceived *prometheus.CounterVec
+	audioBytes     *prometheus.CounterVec
+	reconnects     *prometheus.CounterVec
+	errors         *prometheus.CounterVec
+	tokens         *prometheus.CounterVec
+	turnLatency    prometheus.Histogram
+	state          *prometheus.GaugeVec
+	inputDropped   prometheus.Counter
+	flushed        prometheus.Counter
+}
+
+// NewRealtime registerer 에 실시간 세션 지표를 등록합니다.
+func NewRealtime(registerer prometheus.Registerer) *Realtime {
+	factory := promauto.With(registerer)
+	return &Realtime{
+		eventsSent: factory.NewCounterVec(prometheus.CounterOpts{
+			Name: "realtime_client_events_sent_total",
+			Help: "Client events sent to the Realtime API.",
+		}, []string{"type"}),
+		eventsReceived: factory.NewCounterVec(prometheus.CounterOpts{
+			Name: "realtime_server_events_received_total",
+			Help: "Server events received from the Realtime API.",
+		}, []string{"type"}),
+		audioBytes: factory.NewCounterVec(prometheus.CounterOpts{
+			Name: "realtime_audio_bytes_total",
+			Help: "PCM16 audio bytes sent (in) and received (out).",
+		}, []string{"direction"}),
+		reconnects: factory.NewCounterVec(prometheus.CounterOpts{
+			Name: "realtime_reconnects_total",
+			Help: "Reconnect attempts by outcome.",
+		}, []string{"state"}),
+		errors: factory.NewCounterVec(prometheus.CounterOpts{
+			Name: "realtime_errors_total",
+			Help: "Server errors and failed responses by class and code.",
+		}, []string{"class", "code"}),
+		tokens: factory.NewCounterVec(prometheus.CounterOpts{
+			Name: "realtime_tokens_total",
+			Help: "Tokens used by responses.",
+		}, []string{"direction", "modality"}),
+		turnLatency: factory.NewHistogram(prometheus.HistogramOpts{
+			Name:    "realtime_turn_latency_seconds",
+			Help:    "Time from the end of user input until the response is heard (or first output when playback is not tracked).",
+			Buckets: turnLatencyBuckets,
+		}),
+		state: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "realtime_session_state",
+			Help: "Current session state (1 for the current state, 0 otherwise).",
+		}, []string{"state"}),
+		inputDropped: factory.NewCounter(prometheus.CounterOpts{
+			Name: "realtime_input_dropped_seconds_total",
+			Help: "Microphone audio discarded because the input channel was full.",
+		}),
+		flushed: factory.NewCounter(prometheus.CounterOpts{
+			Name: "realtime_playback_flushed_seconds_total",
+			Help: "Queued assistant audio discarded by barge-in.",
+		}),
+	}
+}
+
+// add 카운터를 v 만큼 증가시킵니다. (음수면 카운터가 panic 하므로 무시)
+func add(counter prometheus.Counter, v float64) {
+	if v > 0 {
+		counter.Add(v)
+	}
+}
+
+// openai.Metrics
+
+var _ openai.Metrics = (*Realtime)(nil)
+
+func (m *Realtime) EventSent(eventType string) {
+	m.eventsSent.WithLabelValues(eventType).Inc()
+}
+
+func (m *Realtime) EventReceived(eventType string) {
+	m.eventsReceived.WithLabelValues(eventType).Inc()
+}
+
+func (m *Realtime) AudioSent(bytes int) {
+	add(m.audioBytes.WithLabelValues("in"), float64(bytes))
+}
+
+func (m *Realtime) AudioReceived(bytes int) {
+	add(m.audioBytes.WithLabelValues("out"), float64(bytes))
+}
+
+func (m *Realtime) Reconnect(state string) {
+	m.reconnects.WithLabelValues(state).Inc()
+}
+
+func (m *Realtime) Error(class openai.ErrorClass, code string) {
+	m.errors.WithLabelValues(string(class), code).Inc()
+}
+
+func (m *Realtime) TokensUsed(usage events.Usage) {
+	input, output := usage.InputTokenDetails, usage.OutputTokenDetails
+	add(m.tokens.WithLabelValues("input", "text"), float64(input.TextTokens-input.CachedTokensDetails.TextTokens))
+	add(m.tokens.WithLabelValues("input", "audio"), float64(input.AudioTokens-input.CachedTokensDetails.AudioTokens))
+	add(m.tokens.WithLabelValues("cached_input", "text"), float64(input.CachedTokensDetails.TextTokens))
+	add(m.tokens.WithLabelValues("cached_input", "audio"), float64(input.CachedTokensDetails.AudioTokens))
+	add(m.tokens.WithLabelValues("output", "text"), float64(output.TextTokens))
+	add(m.tokens.WithLabelValues("output", "audio"), float64(output.AudioTokens))
+}
+
+func (m *Realtime) TurnLatency(latency time.Duration) {
+	m.turnLatency.Observe(latency.Seconds())
+}
+
+func (m *Realtime) StateChanged(from, to openai.State) {
+	if from != "" {
+		m.state.WithLabelValues(string(from)).Set(0)
+	}
+	m.state.WithLabelValues(string(to)).Set(1)
+}
+
+// audiomanager.Metrics
+
+func (m *Realtime) InputDropped(d time.Duration) {
+	add(m.inputDropped, d.Seconds())
+}
+
+func (m *Realtime) PlaybackFlushed(d time.Duration) {
+	add(m.flushed, d.Seconds())
+}
+
+// Serve addr 에서 gatherer 의 지표를 /metrics 로 제공합니다. ctx 가 끝나면 서버를 종료합니다. (go routine)
+func Serve(ctx context.Context, addr string, gatherer prometheus.Gatherer) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{ErrorLog: log}))
+	server := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	log.Infof("Serving metrics on http://%s/metrics", addr)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"openai-realtime/pkg/openai"
+	"openai-realtime/pkg/openai/events"
+	"openai-realtime/pkg/openai/openaitest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testTimeout = 2 * time.Second
+
+// newTestClient 계측을 등록하고 모의 서버에 연결한 클라이언트
+func newTestClient(t *testing.T, m *Realtime) (*openai.Client, *openaitest.Server) {
+	t.Helper()
+
+	srv := openaitest.NewServer()
+	ctx, cancel := context.WithCancel(context.Background())
+	c, err := openai.NewClient(ctx, srv.URL(), "/v1/realtime", "gpt-4o-realtime-preview-2024-10-01", "test-key")
+	if err != nil {
+		cancel()
+		srv.Close()
+		t.Fatalf("NewClient: %v", err)
+	}
+	c.SetMetrics(m)
+	go c.ReceiveServerEvent(ctx, cancel)
+
+	t.Cleanup(func() {
+		_ = c.Close()
+		cancel()
+		srv.Close()
+	})
+	return c, srv
+}
+
+func receive[T any](t *testing.T, ch <-chan T, what string) T {
+	t.Helper()
+	select {
+	case v := <-ch:
+		return v
+	case <-time.After(testTimeout):
+		t.Fatalf("timed out waiting for %s", what)
+		return *new(T)
+	}
+}
+
+func TestRealtimeClientMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	m := NewRealtime(registry)
+	c, srv := newTestClient(t, m)
+
+	turns := make(chan openai.TurnTiming, 1)
+	c.Latency().OnTurn(func(turn openai.TurnTiming) { turns <- turn })
+	done := make(chan struct{}, 1)
+	openai.On(c, events.ResponseDoneEventType, func(ctx context.Context, e events.ResponseDone) {
+		done <- struct{}{}
+	})
+
+	srv.Script(openaitest.Response{Text: "hello"})
+	if err := c.ConversationItemCreate("hi", "user"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ResponseCreate(nil); err != nil {
+		t.Fatal(err)
+	}
+	receive(t, done, "response.done")
+	receive(t, turns, "turn latency") // OnTurn 은 TurnLatency 보고 뒤에 호출됨
+
+	srv.InjectError("invalid_request_error", "invalid_value", "bad value", "")
+	var apiErr *openai.APIError
+	if err := receive(t, c.ErrChan, "error"); !errors.As(err, &apiErr) {
+		t.Fatalf("got %v, want *APIError", err)
+	}
+
+	counters := []struct {
+		name   string
+		metric prometheus.Collector
+		want   float64
+	}{
+		{"sent conversation.item.create", m.eventsSent.WithLabelValues(openai.ConversationItemCreateEventType), 1},
+		{"sent response.create", m.eventsSent.WithLabelValues(openai.ResponseCreateEventType), 1},
+		{"received response.done", m.eventsReceived.WithLabelValues(events.ResponseDoneEventType), 1},
+		{"received text deltas", m.eventsReceived.WithLabelValues(events.ResponseTextDeltaEventType), 1},
+		{"output text tokens", m.tokens.WithLabelValues("output", "text"), 5},
+		{"errors", m.errors.WithLabelValues(string(apiErr.Class), "invalid_value"), 1},
+		{"ready state", m.state.WithLabelValues(string(openai.StateReady)), 1},
+		{"responding state", m.state.WithLabelValues(string(openai.StateResponding)), 0},
+	}
+	for _, tt := range counters {
+		if got := testutil.ToFloat64(tt.metric); got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	var latency dto.Metric
+	if err := m.turnLatency.Write(&latency); err != nil {
+		t.Fatal(err)
+	}
+	if got := latency.GetHistogram().GetSampleCount(); got != 1 {
+		t.Errorf("turn latency samples = %d, want 1", got)
+	}
+
+	// 등록된 지표 이름과 레이블
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(`
+# HELP realtime_client_events_sent_total Client events sent to the Realtime API.
+# TYPE realtime_client_events_sent_total counter
+realtime_client_events_sent_total{type="conversation.item.create"} 1
+realtime_client_events_sent_total{type="response.create"} 1
+`), "realtime_client_events_sent_total"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRealtimeMetricNames(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	m := NewRealtime(registry)
+
+	m.AudioSent(4800)
+	m.AudioReceived(9600)
+	m.AudioReceived(-1) // 음수는 무시
+	m.Reconnect(openai.ReconnectStateReconnected)
+	m.TurnLatency(600 * time.Millisecond)
+	m.TurnLatency(4 * time.Second)
+	m.StateChanged("", openai.StateReady)
+	m.StateChanged(openai.StateReady, openai.StateResponding)
+	m.InputDropped(250 * time.Millisecond)
+	m.PlaybackFlushed(time.Second)
+
+	expected := `
+# HELP realtime_audio_bytes_total PCM16 audio bytes sent (in) and received (out).
+# TYPE realtime_audio_bytes_total counter
+realtime_audio_bytes_total{direction="in"} 4800
+realtime_audio_bytes_total{direction="out"} 9600
+# HELP realtime_input_dropped_seconds_total Microphone audio discarded because the input channel was full.
+# TYPE realtime_input_dropped_seconds_total counter
+realtime_input_dropped_seconds_total 0.25
+# HELP realtime_playback_flushed_seconds_total Queued assistant audio discarded by barge-in.
+# TYPE realtime_playback_flushed_seconds_total counter
+realtime_playback_flushed_seconds_total 1
+# HELP realtime_reconnects_total Reconnect attempts by outcome.
+# TYPE realtime_reconnects_total counter
+realtime_reconnects_total{state="reconnected"} 1
+# HELP realtime_session_state Current session state (1 for the current state, 0 otherwise).
+# TYPE realtime_session_state gauge
+realtime_session_state{state="ready"} 0
+realtime_session_state{state="responding"} 1
+# HELP realtime_turn_latency_seconds Time from the end of user input until the response is heard (or first output when playback is not tracked).
+# TYPE realtime_turn_latency_seconds histogram
+realtime_turn_latency_seconds_bucket{le="0.25"} 0
+realtime_turn_latency_seconds_bucket{le="0.5"} 0
+realtime_turn_latency_seconds_bucket{le="0.75"} 1
+realtime_turn_latency_seconds_bucket{le="1"} 1
+realtime_turn_latency_seconds_bucket{le="1.5"} 1
+realtime_turn_latency_seconds_bucket{le="2"} 1
+realtime_turn_latency_seconds_bucket{le="3"} 1
+realtime_turn_latency_seconds_bucket{le="5"} 2
+realtime_turn_latency_seconds_bucket{le="10"} 2
+realtime_turn_latency_seconds_bucket{le="+Inf"} 2
+realtime_turn_latency_seconds_sum 4.6
+realtime_turn_latency_seconds_count 2
+`
+	names := []string{
+		"realtime_audio_bytes_total",
+		"realtime_input_dropped_seconds_total",
+		"realtime_playback_flushed_seconds_total",
+		"realtime_reconnects_total",
+		"realtime_session_state",
+		"realtime_turn_latency_seconds",
+	}
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(expected), names...); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,103 @@
+package openai
+
+import (
+	"context"
+	"openai-realtime/pkg/openai/events"
+	"sync"
+)
+
+// PlaybackController barge-in 시 어시스턴트 음성 재생을 멈추는 출력 측 인터페이스
+type PlaybackController interface {
+	// StopPlayback 재생 대기 중인 음성을 모두 버리고 페이드 아웃합니다.
+	StopPlayback()
+	// PlayedMs itemID 아이템이 실제로 스피커에서 재생된 길이(ms)를 반환합니다.
+	// 재생된 적이 없으면 ok 는 false 입니다.
+	PlayedMs(itemID string) (playedMs int, ok bool)
+}
+
+// BargeIn 사용자가 말을 시작하면 어시스턴트 음성을 중단하고 들은 만큼만 남도록 잘라냅니다.
+type BargeIn struct {
+	client       *Client
+	playback     PlaybackController
+	unsubscribes []func()
+
+	mu           sync.Mutex
+	responding   bool   // response.created ~ response.done 사이 여부
+	itemID       string // 현재 재생 중인 어시스턴트 음성 아이템
+	contentIndex int
+}
+
+// EnableBargeIn input_audio_buffer.speech_started 수신 시 재생을 멈추고,
+// 실제로 재생된 만큼 conversation.item.truncate 를 전송하며, 응답이 진행 중이면 response.cancel 을 전송합니다.
+// 서버 VAD 를 사용하지 않는 경우 말하기 시작을 검출한 쪽에서 Interrupt 를 직접 호출합니다.
+func EnableBargeIn(c *Client, playback PlaybackController) *BargeIn {
+	b := &BargeIn{client: c, playback: playback}
+
+	b.unsubscribes = []func(){
+		On(c, events.ResponseCreatedEventType, func(ctx context.Context, e events.ResponseCreated) {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			b.responding = true
+		}),
+		On(c, events.ResponseDoneEventType, func(ctx context.Context, e events.ResponseDone) {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			b.responding = false // 아이템은 유지: 서버 전송이 끝나도 재생은 계속될 수 있음
+		}),
+		On(c, events.ResponseAudioDeltaEventType, func(ctx context.Context, e events.ResponseAudioDelta) {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			b.itemID = e.ItemID
+			b.contentIndex = e.ContentIndex
+		}),
+		On(c, events.InputAudioBufferSpeechStartedEventType, func(ctx context.Context, e events.InputAudioBufferSpeechStarted) {
+			b.Interrupt()
+		}),
+	}
+	return b
+}
+
+// Disable barge-in 을 비활성화합니다.
+func (b *BargeIn) Disable() {
+	for _, unsubscribe := range b.unsubscribes {
+		unsubscribe()
+	}
+}
+
+// Interrupt 재생 중인 어시스턴트 음성을 중단하고, 응답이 진행 중이면 취소합니다.
+// 응답이 끝난 뒤에도 남은 음성을 재생 중이면 들은 위치에서 자르며, 모두 재생된 아이템이나
+// 음성 아이템이 없는 경우(텍스트나 함수 호출을 생성 중)에는 자르지 않습니다.
+func (b *BargeIn) Interrupt() {
+	b.mu.Lock()
+	itemID, contentIndex, responding := b.itemID, b.contentIndex, b.responding
+	b.itemID = ""
+	b.mu.Unlock()
+
+	// 멈추는 시점까지 들은 위치에서 자릅니다. (페이드 아웃 구간은 들은 것으로 보지 않음)
+	var playedMs int
+	if itemID != "" {
+		// 이후 도착하는 같은 아이템의 음성은 재생하지 않습니다.
+		b.client.muteAudio(itemID)
+		playedMs, _ = b.playback.PlayedMs(itemID)
+	}
+	b.playback.StopPlayback()
+
+	if responding {
+		if err := b.client.ResponseCancel(); err != nil {
+			log.Errorf("Failed to cancel response: %v", err)
+		}
+	}
+
+	if itemID == "" {
+		return // 잘라낼 어시스턴트 음성이 없음
+	}
+	item, ok := b.client.conversation.Item(itemID)
+	if !ok || playedMs >= item.AudioDurationMs {
+		return // 모두 재생되었으므로 잘라낼 필요 없음
+	}
+	log.Infof("Barge-in: truncating %s at %d ms (%d ms not played)", itemID, playedMs, item.AudioDurationMs-playedMs)
+
+	if err := b.client.ConversationItemTruncate(itemID, contentIndex, playedMs); err != nil {
+		log.Errorf("Failed to truncate item %s: %v", itemID, err)
+	}
+}
@@ -0,0 +1,137 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"openai-realtime/pkg/openai/events"
+	"openai-realtime/pkg/openai/openaitest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakePlayback 재생 중단 횟수와 재생 위치를 흉내 내는 PlaybackController
+type fakePlayback struct {
+	mu       sync.Mutex
+	stops    int
+	playedMs int
+}
+
+func (p *fakePlayback) StopPlayback() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stops++
+}
+
+func (p *fakePlayback) PlayedMs(itemID string) (int, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.playedMs, true
+}
+
+// waitForEvent eventType 의 서버 이벤트가 처리될 때까지 기다리는 함수를 반환합니다.
+func waitForEvent(t *testing.T, c *Client, eventType string) func() {
+	t.Helper()
+	handled := make(chan struct{}, 10)
+	c.OnRaw(eventType, func(ctx context.Context, eventType string, message []byte) {
+		handled <- struct{}{}
+	})
+	return func() { receive(t, handled, eventType) }
+}
+
+func TestBargeInCancelsResponseWithoutAudio(t *testing.T) {
+	c, srv := newTestClient(t)
+	playback := &fakePlayback{}
+	bargeIn := EnableBargeIn(c, playback)
+	created := waitForEvent(t, c, events.ResponseCreatedEventType)
+	done := waitForEvent(t, c, events.ResponseDoneEventType)
+
+	// 텍스트를 생성 중이라 아직 음성 아이템이 없는 응답
+	srv.Emit(map[string]interface{}{"type": events.ResponseCreatedEventType, "response": map[string]interface{}{"id": "resp_1", "status": "in_progress"}})
+	created()
+
+	bargeIn.Interrupt()
+	if _, err := srv.WaitFor(ResponseCancelEventType, 1, testTimeout); err != nil {
+		t.Fatal(err)
+	}
+	if playback.stops != 1 {
+		t.Errorf("StopPlayback called %d times, want 1", playback.stops)
+	}
+
+	srv.Emit(map[string]interface{}{"type": events.ResponseDoneEventType, "response": map[string]interface{}{"id": "resp_1", "status": "cancelled"}})
+	done()
+	bargeIn.Interrupt()
+	time.Sleep(50 * time.Millisecond)
+	if got := len(filterReceived(srv, ResponseCancelEventType)); got != 1 {
+		t.Errorf("sent %d response.cancel, want 1 (no response in progress)", got)
+	}
+}
+
+func TestBargeInTruncatesPlayingAudio(t *testing.T) {
+	c, srv := newTestClient(t)
+	playback := &fakePlayback{playedMs: 50}
+	bargeIn := EnableBargeIn(c, playback)
+	done := waitForEvent(t, c, events.ResponseDoneEventType)
+	// barge-in 이 아이템을 기록한 뒤에 호출되도록 EnableBargeIn 다음에 구독합니다.
+	deltas := make(chan events.ResponseAudioDelta, 10)
+	On(c, events.ResponseAudioDeltaEventType, func(ctx context.Context, e events.ResponseAudioDelta) {
+		deltas <- e
+	})
+
+	srv.Script(openaitest.Response{Audio: make([]byte, 9600)}) // 200ms
+	if err := c.ResponseCreate(nil); err != nil {
+		t.Fatal(err)
+	}
+	delta := receive(t, deltas, "audio delta")
+	done()
+	for len(deltas) > 0 {
+		<-deltas
+	}
+
+	// response.done 이후에도 재생 중인 나머지는 들은 위치에서 자릅니다.
+	bargeIn.Interrupt()
+	truncated, err := srv.WaitFor(ConversationItemTruncateEventType, 1, testTimeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var e events.ConversationItemTruncate
+	if err := json.Unmarshal(truncated[0].Raw, &e); err != nil {
+		t.Fatal(err)
+	}
+	if e.ItemID != delta.ItemID || e.AudioEndMs != 50 {
+		t.Errorf("truncate after response.done = %+v, want %s at 50ms", e, delta.ItemID)
+	}
+	if got := filterReceived(srv, ResponseCancelEventType); len(got) != 0 {
+		t.Errorf("sent %d response.cancel after response.done", len(got))
+	}
+
+	// 응답 중 barge-in 은 취소하고 들은 위치에서 자릅니다. (100ms 씩 두 번에 나누어 전송)
+	srv.Script(openaitest.Response{Audio: make([]byte, 9600), ChunkSize: 4800, Delay: 200 * time.Millisecond})
+	go func() { _ = c.ResponseCreate(nil) }()
+	delta = receive(t, deltas, "audio delta")
+	bargeIn.Interrupt()
+
+	truncated, err = srv.WaitFor(ConversationItemTruncateEventType, 2, testTimeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := srv.WaitFor(ResponseCancelEventType, 1, testTimeout); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(truncated[1].Raw, &e); err != nil {
+		t.Fatal(err)
+	}
+	if e.ItemID != delta.ItemID || e.AudioEndMs != 50 {
+		t.Errorf("truncate = %+v, want %s at 50ms", e, delta.ItemID)
+	}
+}
+
+func filterReceived(srv *openaitest.Server, eventType string) []openaitest.ReceivedEvent {
+	var matched []openaitest.ReceivedEvent
+	for _, e := range srv.Received() {
+		if e.Type == eventType {
+			matched = append(matched, e)
+		}
+	}
+	return matched
+}
@@ -2,8 +2,8 @@ package openai
 
 import (
 	"encoding/base64"
+	"errors"
 	"fmt"
-	"openai-realtime/pkg/config"
 	"openai-realtime/pkg/openai/events"
 )
 
@@ -12,28 +12,63 @@ const (
 	ConversationItemCreateEventType = "conversation.item.create"
 	InputAudioBufferAppendEventType = "input_audio_buffer.append"
 	InputAudioBufferCommitEventType = "input_audio_buffer.commit"
+	InputAudioBufferClearEventType  = "input_audio_buffer.clear"
+	ResponseCreateEventType         = "response.create"
+	ConversationItemDeleteEventType = "conversation.item.delete"
+
+	ResponseCancelEventType           = "response.cancel"
+	ConversationItemTruncateEventType = "conversation.item.truncate"
 )
 
-// 로그 클라이언트 이벤트 (go routine)
-func (c *Client) SessionUpdate(inputAudioTranscription events.InputAudioTranscription, turnDetection events.TurnDetection, tools []events.Tool) error {
-	sessionUpdate := events.SessionUpdate{
-		Modalities:              []string{"text", "audio"},
-		Instructions:            config.SystemPrompt(),
-		Voice:                   "alloy",
-		InputAudioFormat:        "pcm16",
-		OutputAudioFormat:       "pcm16",
-		InputAudioTranscription: &inputAudioTranscription,
-		TurnDetection:           &turnDetection,
-		Tools:                   tools,
-		ToolChoice:              "auto",
-		Temperature:             1.2,
-		MaxResponseOutputTokens: 1024,
+// SessionUpdate 옵션으로 세션 설정을 만들어 검증한 뒤 전송합니다. (NewSessionConfig 참고)
+// 턴 감지는 기본적으로 서버 VAD (DefaultTurnDetection) 이며, WithoutTurnDetection 또는 WithTurnDetection(nil) 을
+// 주면 turn_detection 을 null 로 보내 서버 VAD 를 끕니다. 이때는 클라이언트가 직접 commit 하고 응답을 요청해야 합니다.
+// 검증에 실패하면 전송하지 않고 잘못된 항목을 모두 담은 오류를 반환합니다.
+func (c *Client) SessionUpdate(opts ...SessionOption) error {
+	config, err := NewSessionConfig(opts...)
+	if err != nil {
+		return err
+	}
+
+	c.sessionMu.Lock()
+	defer c.sessionMu.Unlock()
+	return c.sendSessionConfig(config)
+}
+
+// AmendSession 마지막으로 전송한 세션 설정에 옵션을 덧붙여 다시 전송합니다.
+// 지정하지 않은 항목은 그대로 유지되며, 아직 세션 설정을 보내지 않았으면 SessionUpdate 와 같습니다.
+func (c *Client) AmendSession(opts ...SessionOption) error {
+	c.sessionMu.Lock()
+	defer c.sessionMu.Unlock()
+
+	if c.sessionConfig == nil {
+		config, err := NewSessionConfig(opts...)
+		if err != nil {
+			return err
+		}
+		return c.sendSessionConfig(config)
+	}
+
+	config := *c.sessionConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if err := config.Validate(); err != nil {
+		return err
 	}
+	return c.sendSessionConfig(&config)
+}
+
+// sendSessionConfig 세션 설정을 기록하고 전송합니다. (c.sessionMu 를 잡은 상태에서 호출)
+func (c *Client) sendSessionConfig(config *SessionConfig) error {
+	sessionUpdate := config.event()
+	c.session = sessionUpdate
+	c.sessionConfig = config
 
 	return c.sendEvent(events.ClientEvent{
 		EventID: generateEventID(),
 		Type:    SessionUpdateEventType,
-		Session: &sessionUpdate,
+		Session: sessionUpdate,
 	}, true)
 }
 
@@ -53,23 +88,46 @@ func (c *Client) ConversationItemCreate(content string, role string) error {
 	}, true)
 }
 
+// FunctionCallOutput 함수 호출 결과를 function_call_output 아이템으로 전송합니다.
+func (c *Client) FunctionCallOutput(callID string, output string) error {
+	if callID == "" {
+		return fmt.Errorf("call id is required")
+	}
+
+	item := events.Item{
+		Type:   events.ItemTypeFunctionCallOutput,
+		CallID: callID,
+		Output: output,
+	}
+
+	return c.sendEvent(events.ClientEvent{
+		EventID: generateEventID(),
+		Type:    ConversationItemCreateEventType,
+		Item:    &item,
+	}, true)
+}
+
 func (c *Client) SendInputAudioBufferAppend(data []byte) error {
 	fmt.Print(".")
 	if len(data) == 0 {
 		return fmt.Errorf("no streamAudio data to send")
 	}
 
-	if c.status != StatusReady {
+	if !c.State().sessionActive() {
 		log.Warn("Cannot send audio data when not ready")
 		return nil
 	}
 
 	encoded := base64.StdEncoding.EncodeToString(data)
-	return c.sendEvent(events.ClientEvent{
+	if err := c.sendEvent(events.ClientEvent{
 		EventID: generateEventID(),
 		Type:    InputAudioBufferAppendEventType,
 		Audio:   &encoded,
-	}, false)
+	}, false); err != nil {
+		return err
+	}
+	c.metricsHook().AudioSent(len(data))
+	return nil
 }
 
 func (c *Client) SendInputAudioBufferCommit() error {
@@ -78,3 +136,74 @@ func (c *Client) SendInputAudioBufferCommit() error {
 		Type:    InputAudioBufferCommitEventType,
 	}, true)
 }
+
+func (c *Client) SendInputAudioBufferClear() error {
+	return c.sendEvent(events.InputAudioBufferClear{
+		EventID: generateEventID(),
+		Type:    InputAudioBufferClearEventType,
+	}, true)
+}
+
+// ErrResponsesClosed CloseResponses 이후 ResponseCreate 를 호출하면 반환됩니다.
+var ErrResponsesClosed = errors.New("responses are closed")
+
+// CloseResponses 이후의 ResponseCreate 를 보내지 않고 ErrResponsesClosed 를 반환하게 합니다.
+// 세션을 끝내기 전 마지막 응답을 재생하는 동안 새 응답이 시작되지 않도록 할 때 사용합니다.
+func (c *Client) CloseResponses() {
+	c.responsesClosed.Store(true)
+}
+
+// ResponseCreate 응답 생성을 요청합니다. config 가 nil 이면 세션 설정을 그대로 사용합니다.
+// 남은 한도가 부족하면 초기화될 때까지 기다리거나 *RateLimitError 를 반환합니다. (RateLimitPolicy 참고)
+func (c *Client) ResponseCreate(config *events.ResponseConfig) error {
+	if c.responsesClosed.Load() {
+		return ErrResponsesClosed
+	}
+	if err := c.limiter.wait(c.done); err != nil {
+		return err
+	}
+	return c.sendEvent(events.ResponseCreate{
+		EventID:  generateEventID(),
+		Type:     ResponseCreateEventType,
+		Response: config,
+	}, true)
+}
+
+// ResponseCancel 진행 중인 응답을 취소합니다. 대기 중인 오디오보다 먼저 전송됩니다.
+func (c *Client) ResponseCancel() error {
+	return c.sendEvent(events.ResponseCancel{
+		EventID: generateEventID(),
+		Type:    ResponseCancelEventType,
+	}, true)
+}
+
+// ConversationItemTruncate 어시스턴트 음성 아이템을 audioEndMs 까지만 들은 것으로 잘라냅니다.
+func (c *Client) ConversationItemTruncate(itemID string, contentIndex int, audioEndMs int) error {
+	if itemID == "" {
+		return fmt.Errorf("item id is required")
+	}
+	if audioEndMs < 0 {
+		return fmt.Errorf("audio_end_ms must not be negative: %d", audioEndMs)
+	}
+
+	return c.sendEvent(events.ConversationItemTruncate{
+		EventID:      generateEventID(),
+		Type:         ConversationItemTruncateEventType,
+		ItemID:       itemID,
+		ContentIndex: contentIndex,
+		AudioEndMs:   audioEndMs,
+	}, true)
+}
+
+// ConversationItemDelete 대화에서 아이템을 삭제합니다.
+func (c *Client) ConversationItemDelete(itemID string) error {
+	if itemID == "" {
+		return fmt.Errorf("item id is required")
+	}
+
+	return c.sendEvent(events.ConversationItemDelete{
+		EventID: generateEventID(),
+		Type:    ConversationItemDeleteEventType,
+		ItemID:  itemID,
+	}, true)
+}
@@ -10,20 +10,23 @@ import (
 	"openai-realtime/pkg/config"
 	"openai-realtime/pkg/openai/events"
 	"os"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
 const (
-	StatusConnected  = "connected"
-	StatusClosed     = "closed"
-	StatusWaiting    = "waiting"
-	StatusReady      = "ready"
-	StatusProcessing = "processing"
-
 	reconnectInterval    = 5 * time.Second
+	maxReconnectInterval = 30 * time.Second
 	maxReconnectAttempts = 5
 )
 
+const (
+	ReconnectStateReconnecting = "reconnecting"
+	ReconnectStateReconnected  = "reconnected"
+	ReconnectStateFailed       = "failed"
+)
+
 var log = func() *logrus.Logger {
 	log := logrus.New()
 	log.SetOutput(os.Stdout)
@@ -31,17 +34,60 @@ var log = func() *logrus.Logger {
 	return log
 }()
 
+// ReconnectEvent 재연결 진행 상황 알림
+type ReconnectEvent struct {
+	State   string
+	Attempt int
+	Err     error
+}
+
+// AudioChunk 응답 음성 조각 (pcm16 24kHz mono)과 그 출처
+type AudioChunk struct {
+	ResponseID   string
+	ItemID       string
+	ContentIndex int
+	Data         []byte
+}
+
 // 클라이언트 설정 구조체
 type Client struct {
 	apiKey string
 	conn   *websocket.Conn
+	reader *connReader // conn 의 수신 goroutine
+	connMu sync.Mutex
 	host   string
 	path   string
 	model  string
+	closed bool
+
+	state           *stateMachine
+	limiter         *rateLimiter
+	responsesClosed atomic.Bool  // CloseResponses 이후 response.create 를 보내지 않음
+	metrics         atomic.Value // metricsHolder (SetMetrics 참고)
+	latency         *LatencyTracker
+	AudioOutputChan chan AudioChunk
+	ErrChan         chan error // 세션을 유지하는 오류 (*APIError, *ResponseError)
+	ReconnectChan   chan ReconnectEvent
 
-	status          string
-	AudioOutputChan chan []byte
-	ErrChan         chan error
+	controlQueue chan outbound // 우선 전송 큐 (writeLoop 에서 소비)
+	normalQueue  chan outbound // 일반 전송 큐 (writeLoop 에서 소비)
+	done         chan struct{}
+	writerDone   chan struct{} // writeLoop 종료 시 닫힘
+	closeOnce    sync.Once
+
+	finished   chan struct{} // 수신 루프 종료 시 닫힘 (Wait)
+	finishOnce sync.Once
+	err        error // 수신 루프 종료 사유 (정상 종료면 nil)
+
+	dispatcher *dispatcher
+
+	audioMu     sync.Mutex
+	mutedItemID string // barge-in 으로 중단된 아이템 (이후 음성은 재생하지 않음)
+
+	sessionMu     sync.Mutex
+	session       *events.SessionUpdate // 재연결 시 다시 전송할 마지막 세션 설정
+	sessionConfig *SessionConfig        // 마지막으로 전송한 세션 설정 (AmendSession 의 기준)
+	conversation  *Conversation         // 서버 대화 상태 미러 (재연결 시 다시 재생)
 
 	reconnectAttempts int
 }
@@ -53,14 +99,32 @@ func NewClient(ctx context.Context, host, path, model, apiKey string) (*Client,
 		model:           model,
 		path:            path,
 		apiKey:          apiKey,
-		status:          StatusClosed,
-		AudioOutputChan: make(chan []byte, 10),
-		ErrChan:         make(chan error, 1),
+		state:           newStateMachine(),
+		limiter:         newRateLimiter(),
+		AudioOutputChan: make(chan AudioChunk, 10),
+		ErrChan:         make(chan error, 10),
+		ReconnectChan:   make(chan ReconnectEvent, 10),
+		controlQueue:    make(chan outbound, controlQueueSize),
+		normalQueue:     make(chan outbound, normalQueueSize),
+		done:            make(chan struct{}),
+		writerDone:      make(chan struct{}),
+		finished:        make(chan struct{}),
+		dispatcher:      newDispatcher(),
+		conversation:    newConversation(),
 	}
+	client.latency = newLatencyTracker(func(turn TurnTiming) {
+		client.metricsHook().TurnLatency(turn.Breakdown().Total)
+	})
+	client.state.subscribe(func(change StateChange) {
+		client.metricsHook().StateChanged(change.From, change.To)
+	})
+	client.registerCoreHandlers()
 
 	if err := client.Connect(ctx); err != nil {
+		client.setState(StateClosed, "dial failed")
 		return nil, err
 	}
+	go client.writeLoop()
 	return &client, nil
 }
 
@@ -77,6 +141,7 @@ func (c *Client) Connect(ctx context.Context) error {
 	headers.Add("OpenAI-Beta", "realtime=v1")
 
 	urlString := c.getUrl()
+	c.setState(StateConnecting, "connect")
 
 	dialer := websocket.DefaultDialer
 	dialer.HandshakeTimeout = 10 * time.Second
@@ -86,8 +151,11 @@ func (c *Client) Connect(ctx context.Context) error {
 		log.Info("Dial error:", err)
 		return err
 	} else {
+		c.connMu.Lock()
 		c.conn = conn
-		c.status = StatusConnected
+		c.reader = c.startReader(conn)
+		c.closed = false
+		c.connMu.Unlock()
 	}
 
 	log.Info("WebSocket connection established")
@@ -95,50 +163,220 @@ func (c *Client) Connect(ctx context.Context) error {
 	return nil
 }
 
+// Conversation 로컬에 미러링된 대화 상태
+func (c *Client) Conversation() *Conversation {
+	return c.conversation
+}
+
+// Model 연결하는 모델 이름
+func (c *Client) Model() string {
+	return c.model
+}
+
+// muteAudio itemID 아이템의 음성을 더 이상 AudioOutputChan 으로 보내지 않습니다.
+func (c *Client) muteAudio(itemID string) {
+	c.audioMu.Lock()
+	defer c.audioMu.Unlock()
+	c.mutedItemID = itemID
+}
+
+func (c *Client) isAudioMuted(itemID string) bool {
+	c.audioMu.Lock()
+	defer c.audioMu.Unlock()
+	return c.mutedItemID == itemID
+}
+
 // Close WebSocket 연결 종료
+// writeLoop 가 진행 중인 전송을 마치고 끝난 뒤 close 프레임을 보내므로 동시 writer 가 생기지 않습니다.
 func (c *Client) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.done)
+	})
+	<-c.writerDone
 
-	if c.conn == nil {
+	c.connMu.Lock()
+	c.closed = true
+	conn := c.conn
+	c.conn = nil
+	c.connMu.Unlock()
+
+	if conn == nil {
 		return nil
 	}
 
-	err := c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	err := conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
 	if err != nil {
 		log.Error(fmt.Sprintf("Send close message error: %v", err))
 	}
 
-	err = c.conn.Close()
+	err = conn.Close()
+	c.setState(StateClosed, "close") // 상태 관찰자가 클라이언트를 호출할 수 있으므로 connMu 밖에서 호출
 	if err != nil {
 		log.Error(fmt.Sprintf("Connection close error: %v", err))
 		return err
 	}
-
-	c.conn = nil
-	c.status = StatusClosed
 	log.Info("WebSocket connection closed")
 	return nil
 }
 
-// readEvent WebSocket 메시지 읽기
-func (c *Client) readEvent() (messageType int, p []byte, err error) {
-	return c.conn.ReadMessage()
-}
-
-// sendEvent 클라이언트 이벤트 전송
-func (c *Client) sendEvent(event events.ClientEvent, logging bool) error {
+// sendEvent 클라이언트 이벤트를 전송 큐에 넣습니다.
+// 오디오 append 는 큐에 넣는 즉시 반환하고, 그 외 이벤트는 실제 전송 결과를 기다립니다.
+func (c *Client) sendEvent(event events.OpenAIEvent, logging bool) error {
 	eventJSON, err := json.Marshal(event)
 	if err != nil {
 		log.Error("Error marshalling events:", err)
 		return err
 	}
-	err = c.conn.WriteMessage(websocket.TextMessage, eventJSON)
-	if err != nil {
-		log.Error("Error sending events:", err)
+
+	msg := outbound{eventType: event.GetType(), message: eventJSON}
+	if msg.eventType != InputAudioBufferAppendEventType {
+		msg.result = make(chan error, 1)
+	}
+
+	if err := c.enqueue(msg); err != nil {
 		return err
 	}
 
+	if msg.result != nil {
+		select {
+		case err = <-msg.result:
+		case <-c.done:
+			err = ErrClientClosed
+		}
+		if err != nil {
+			return err
+		}
+	}
+
 	if logging {
 		logEventAsJSON("[SEND]", event, eventJSON)
 	}
 	return nil
 }
+
+// isClosed Close 로 의도적으로 종료되었는지 여부
+func (c *Client) isClosed() bool {
+	c.connMu.Lock()
+	defer c.connMu.Unlock()
+	return c.closed
+}
+
+// dropConn 끊어진 연결을 정리합니다. (재연결 전 호출)
+func (c *Client) dropConn() {
+	c.connMu.Lock()
+	defer c.connMu.Unlock()
+
+	if c.conn != nil {
+		_ = c.conn.Close()
+		c.conn = nil
+	}
+	if c.reader != nil {
+		c.reader.abandon()
+		c.reader = nil
+	}
+}
+
+// reconnect 비정상 종료 시 backoff 를 적용하여 재연결하고 세션을 복원합니다.
+func (c *Client) reconnect(ctx context.Context) error {
+	c.dropConn()
+	c.setState(StateReconnecting, "connection lost")
+
+	var lastErr error
+	for c.reconnectAttempts = 1; c.reconnectAttempts <= maxReconnectAttempts; c.reconnectAttempts++ {
+		attempt := c.reconnectAttempts
+		c.notifyReconnect(ReconnectEvent{State: ReconnectStateReconnecting, Attempt: attempt, Err: lastErr})
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(reconnectBackoff(attempt)):
+		}
+
+		if c.isClosed() {
+			return fmt.Errorf("client closed during reconnect")
+		}
+
+		log.Infof("Reconnecting (attempt %d/%d)", attempt, maxReconnectAttempts)
+		if err := c.Connect(ctx); err != nil {
+			log.Warnf("Reconnect attempt %d failed: %v", attempt, err)
+			lastErr = err
+			c.setState(StateReconnecting, "reconnect failed")
+			continue
+		}
+
+		if err := c.restoreSession(); err != nil {
+			log.Warnf("Session restore failed on attempt %d: %v", attempt, err)
+			lastErr = err
+			c.dropConn()
+			c.setState(StateReconnecting, "session restore failed")
+			continue
+		}
+
+		c.reconnectAttempts = 0
+		c.notifyReconnect(ReconnectEvent{State: ReconnectStateReconnected, Attempt: attempt})
+		log.Info("Reconnected and session restored")
+		return nil
+	}
+
+	err := fmt.Errorf("reconnect failed after %d attempts: %w", maxReconnectAttempts, lastErr)
+	c.setState(StateClosed, "reconnect failed")
+	c.notifyReconnect(ReconnectEvent{State: ReconnectStateFailed, Attempt: maxReconnectAttempts, Err: err})
+	return err
+}
+
+// restoreSession 마지막 세션 설정과 대화 아이템을 새 연결에 다시 전송합니다.
+func (c *Client) restoreSession() error {
+	c.sessionMu.Lock()
+	session := c.session
+	c.sessionMu.Unlock()
+
+	if session != nil {
+		if err := c.sendEvent(events.ClientEvent{
+			EventID: generateEventID(),
+			Type:    SessionUpdateEventType,
+			Session: session,
+		}, true); err != nil {
+			return err
+		}
+	}
+
+	for _, item := range c.conversation.replayItems() {
+		item := item
+		if err := c.sendEvent(events.ClientEvent{
+			EventID: generateEventID(),
+			Type:    ConversationItemCreateEventType,
+			Item:    &item,
+		}, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// notifyError 세션을 유지하는 오류 알림 (채널이 가득 차면 버림)
+func (c *Client) notifyError(err error) {
+	select {
+	case c.ErrChan <- err:
+	default:
+		log.Warn("Error channel is full, discarding error:", err)
+	}
+}
+
+// notifyReconnect 재연결 알림 (채널이 가득 차면 버림)
+func (c *Client) notifyReconnect(event ReconnectEvent) {
+	c.metricsHook().Reconnect(event.State)
+	select {
+	case c.ReconnectChan <- event:
+	default:
+		log.Warn("Reconnect channel is full, discarding reconnect event")
+	}
+}
+
+// reconnectBackoff 시도 횟수에 따라 지수적으로 증가하는 대기 시간
+func reconnectBackoff(attempt int) time.Duration {
+	delay := reconnectInterval << (attempt - 1)
+	if delay <= 0 || delay > maxReconnectInterval {
+		return maxReconnectInterval
+	}
+	return delay
+}
@@ -0,0 +1,355 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"openai-realtime/pkg/openai/events"
+	"openai-realtime/pkg/openai/openaitest"
+	"sync"
+	"testing"
+	"time"
+)
+
+const testTimeout = 2 * time.Second
+
+// newTestClient 모의 서버에 연결하고 수신 루프를 시작한 클라이언트 (session.created 수신 후 반환)
+func newTestClient(t *testing.T) (*Client, *openaitest.Server) {
+	t.Helper()
+
+	srv := openaitest.NewServer()
+	ctx, cancel := context.WithCancel(context.Background())
+	c, err := NewClient(ctx, srv.URL(), "/v1/realtime", "gpt-4o-realtime-preview-2024-10-01", "test-key")
+	if err != nil {
+		cancel()
+		srv.Close()
+		t.Fatalf("NewClient: %v", err)
+	}
+	go c.ReceiveServerEvent(ctx, cancel)
+
+	t.Cleanup(func() {
+		_ = c.Close()
+		cancel()
+		srv.Close()
+	})
+
+	// 서버는 연결을 등록한 뒤 session.created 를 보내므로, 그 전에 Emit 한 이벤트는 유실될 수 있음
+	deadline := time.Now().Add(testTimeout)
+	for c.State() != StateReady {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for session.created (state %s)", c.State())
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return c, srv
+}
+
+// receive ch 에서 값 하나를 기다립니다.
+func receive[T any](t *testing.T, ch <-chan T, what string) T {
+	t.Helper()
+	select {
+	case v := <-ch:
+		return v
+	case <-time.After(testTimeout):
+		t.Fatalf("timed out waiting for %s", what)
+		return *new(T)
+	}
+}
+
+func TestSessionUpdateRoundTrip(t *testing.T) {
+	c, srv := newTestClient(t)
+
+	updated := make(chan events.SessionUpdated, 1)
+	On(c, events.SessionUpdatedEventType, func(ctx context.Context, e events.SessionUpdated) {
+		updated <- e
+	})
+
+	if err := c.SessionUpdate(WithInstructions("be brief"), WithInfiniteResponseOutputTokens()); err != nil {
+		t.Fatalf("SessionUpdate: %v", err)
+	}
+
+	e := receive(t, updated, "session.updated")
+	if got := e.Session.MaxResponseOutputTokens; got != "inf" {
+		t.Errorf("max_response_output_tokens = %v, want inf", got)
+	}
+	if got := e.Session.Instructions; got != "be brief" {
+		t.Errorf("instructions = %q, want %q", got, "be brief")
+	}
+
+	sent, err := srv.WaitFor(SessionUpdateEventType, 1, testTimeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var update struct {
+		Session map[string]interface{} `json:"session"`
+	}
+	if err := json.Unmarshal(sent[0].Raw, &update); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := update.Session["tools"]; ok {
+		t.Errorf("session.update sent tools without any tool: %v", update.Session["tools"])
+	}
+	if td, _ := update.Session["turn_detection"].(map[string]interface{}); td["type"] != "server_vad" {
+		t.Errorf("turn_detection = %v, want server_vad by default", update.Session["turn_detection"])
+	}
+
+	if err := c.Err(); err != nil {
+		t.Fatalf("session ended: %v", err)
+	}
+}
+
+func TestMalformedServerEventKeepsSession(t *testing.T) {
+	c, srv := newTestClient(t)
+
+	srv.Emit(map[string]interface{}{"type": events.ResponseTextDeltaEventType, "delta": 42})
+
+	err := receive(t, c.ErrChan, "decode error")
+	if err == nil {
+		t.Fatal("got nil error")
+	}
+
+	// 세션이 유지되어 다음 요청을 처리해야 합니다.
+	srv.Script(openaitest.Response{Text: "still here"})
+	done := make(chan events.ResponseDone, 1)
+	On(c, events.ResponseDoneEventType, func(ctx context.Context, e events.ResponseDone) {
+		done <- e
+	})
+	if err := c.ResponseCreate(nil); err != nil {
+		t.Fatalf("ResponseCreate: %v", err)
+	}
+	if e := receive(t, done, "response.done"); e.Response.Status != "completed" {
+		t.Errorf("response status = %q, want completed", e.Response.Status)
+	}
+	if err := c.Err(); err != nil {
+		t.Fatalf("session ended: %v", err)
+	}
+}
+
+func TestRecoverableErrorKeepsSession(t *testing.T) {
+	c, srv := newTestClient(t)
+
+	srv.InjectError("invalid_request_error", "invalid_value", "bad value", "evt_1")
+
+	err := receive(t, c.ErrChan, "error event")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("got %T %v, want *APIError", err, err)
+	}
+	if apiErr.Class != ErrorClassInvalidRequest || apiErr.EventID != "evt_1" {
+		t.Errorf("got class %s event %s, want %s evt_1", apiErr.Class, apiErr.EventID, ErrorClassInvalidRequest)
+	}
+	if err := c.Err(); err != nil {
+		t.Fatalf("session ended: %v", err)
+	}
+}
+
+func TestFatalErrorEndsSession(t *testing.T) {
+	c, srv := newTestClient(t)
+
+	srv.InjectError("invalid_request_error", "session_expired", "expired", "")
+
+	finished := make(chan error, 1)
+	go func() { finished <- c.Wait() }()
+
+	var apiErr *APIError
+	if err := receive(t, finished, "session end"); !errors.As(err, &apiErr) || !apiErr.Fatal() {
+		t.Fatalf("Wait() = %v, want fatal *APIError", err)
+	}
+}
+
+func TestDispatcher(t *testing.T) {
+	c, srv := newTestClient(t)
+
+	var mu sync.Mutex
+	var text string
+	var raw []string
+	unsubscribe := On(c, events.ResponseTextDeltaEventType, func(ctx context.Context, e events.ResponseTextDelta) {
+		mu.Lock()
+		defer mu.Unlock()
+		text += e.Delta
+	})
+	unsubscribeRaw := c.OnRaw("response.text.*", func(ctx context.Context, eventType string, message []byte) {
+		mu.Lock()
+		defer mu.Unlock()
+		raw = append(raw, eventType)
+	})
+	done := make(chan struct{}, 2)
+	On(c, events.ResponseDoneEventType, func(ctx context.Context, e events.ResponseDone) {
+		done <- struct{}{}
+	})
+
+	srv.Script(openaitest.Response{Text: "hello world", ChunkSize: 5}, openaitest.Response{Text: "ignored"})
+	if err := c.ResponseCreate(nil); err != nil {
+		t.Fatalf("ResponseCreate: %v", err)
+	}
+	receive(t, done, "first response.done")
+
+	mu.Lock()
+	if text != "hello world" {
+		t.Errorf("text = %q, want %q", text, "hello world")
+	}
+	if len(raw) < 2 || raw[len(raw)-1] != events.ResponseTextDoneEventType {
+		t.Errorf("raw events = %v, want deltas followed by %s", raw, events.ResponseTextDoneEventType)
+	}
+	received := len(raw)
+	mu.Unlock()
+
+	unsubscribe()
+	unsubscribeRaw()
+	if err := c.ResponseCreate(nil); err != nil {
+		t.Fatalf("ResponseCreate: %v", err)
+	}
+	receive(t, done, "second response.done")
+
+	mu.Lock()
+	defer mu.Unlock()
+	if text != "hello world" || len(raw) != received {
+		t.Errorf("unsubscribed handlers were called: text %q, raw %v", text, raw)
+	}
+}
+
+func TestMatchEventType(t *testing.T) {
+	tests := []struct {
+		pattern, eventType string
+		want               bool
+	}{
+		{"*", "session.created", true},
+		{"response.*", "response.text.delta", true},
+		{"response.*", "rate_limits.updated", false},
+		{"response.done", "response.done", true},
+		{"response.done", "response.done.extra", false},
+	}
+	for _, tt := range tests {
+		if got := matchEventType(tt.pattern, tt.eventType); got != tt.want {
+			t.Errorf("matchEventType(%q, %q) = %v, want %v", tt.pattern, tt.eventType, got, tt.want)
+		}
+	}
+}
+
+func TestReconnectRestoresSession(t *testing.T) {
+	if testing.Short() {
+		t.Skip("reconnect waits for the backoff interval")
+	}
+	c, srv := newTestClient(t)
+
+	if err := c.SessionUpdate(WithInstructions("remember me")); err != nil {
+		t.Fatalf("SessionUpdate: %v", err)
+	}
+	created := make(chan struct{}, 1)
+	On(c, events.ConversationItemCreatedEventType, func(ctx context.Context, e events.ConversationItemCreated) {
+		created <- struct{}{}
+	})
+	if err := c.ConversationItemCreate("hello", "user"); err != nil {
+		t.Fatalf("ConversationItemCreate: %v", err)
+	}
+	receive(t, created, "conversation.item.created")
+
+	srv.Disconnect()
+
+	deadline := time.After(reconnectInterval + 5*time.Second)
+	for reconnected := false; !reconnected; {
+		select {
+		case e := <-c.ReconnectChan:
+			if e.State == ReconnectStateFailed {
+				t.Fatalf("reconnect failed: %v", e.Err)
+			}
+			reconnected = e.State == ReconnectStateReconnected
+		case <-deadline:
+			t.Fatal("timed out waiting for reconnect")
+		}
+	}
+
+	updates, err := srv.WaitFor(SessionUpdateEventType, 2, testTimeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var restored struct {
+		Session events.SessionUpdate `json:"session"`
+	}
+	if err := json.Unmarshal(updates[1].Raw, &restored); err != nil {
+		t.Fatal(err)
+	}
+	if restored.Session.Instructions != "remember me" {
+		t.Errorf("restored instructions = %q, want %q", restored.Session.Instructions, "remember me")
+	}
+
+	items, err := srv.WaitFor(ConversationItemCreateEventType, 2, testTimeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var replayed struct {
+		Item events.Item `json:"item"`
+	}
+	if err := json.Unmarshal(items[1].Raw, &replayed); err != nil {
+		t.Fatal(err)
+	}
+	if len(replayed.Item.Content) != 1 || replayed.Item.Content[0].Text != "hello" {
+		t.Errorf("replayed item = %+v, want user text hello", replayed.Item)
+	}
+}
+
+func TestCloseResponses(t *testing.T) {
+	c, srv := newTestClient(t)
+
+	c.CloseResponses()
+	if err := c.ResponseCreate(nil); !errors.Is(err, ErrResponsesClosed) {
+		t.Fatalf("ResponseCreate() = %v, want ErrResponsesClosed", err)
+	}
+	if err := c.ConversationItemCreate("still allowed", "user"); err != nil {
+		t.Fatalf("ConversationItemCreate: %v", err)
+	}
+	if got := filterReceived(srv, ResponseCreateEventType); len(got) != 0 {
+		t.Errorf("sent %d response.create after CloseResponses", len(got))
+	}
+}
+
+func TestCloseStateObserverCanUseClient(t *testing.T) {
+	c, _ := newTestClient(t)
+
+	closed := make(chan bool, 1)
+	c.OnStateChange(func(change StateChange) {
+		if change.To == StateClosed {
+			closed <- c.isClosed() // connMu 를 잡은 채로 호출되면 교착 상태
+		}
+	})
+
+	finished := make(chan error, 1)
+	go func() { finished <- c.Close() }()
+	if err := receive(t, finished, "Close"); err != nil {
+		t.Fatalf("Close() = %v", err)
+	}
+	if !receive(t, closed, "closed state") {
+		t.Error("observer saw the client as not closed")
+	}
+}
+
+func TestCloseWhileSending(t *testing.T) {
+	c, _ := newTestClient(t)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 100)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				errs <- c.ConversationItemCreate("hello", "user")
+			}
+		}()
+	}
+	time.Sleep(5 * time.Millisecond)
+	if err := c.Close(); err != nil {
+		t.Errorf("Close() = %v", err)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil && !errors.Is(err, ErrClientClosed) {
+			t.Errorf("send during Close = %v, want nil or ErrClientClosed", err)
+		}
+	}
+	if err := c.ConversationItemCreate("late", "user"); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("send after Close = %v, want ErrClientClosed", err)
+	}
+}
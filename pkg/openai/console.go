@@ -0,0 +1,35 @@
+package openai
+
+import (
+	"context"
+	"fmt"
+	"openai-realtime/pkg/openai/events"
+)
+
+// SubscribeConsole 응답 텍스트와 음성 전사를 표준 출력에 그대로 출력하는 기본 구독자를 등록합니다.
+// 반환된 함수를 호출하면 모든 출력 구독이 해제됩니다.
+func SubscribeConsole(c *Client) (unsubscribe func()) {
+	unsubscribes := []func(){
+		On(c, events.ResponseTextDeltaEventType, func(ctx context.Context, e events.ResponseTextDelta) {
+			fmt.Print(e.Delta)
+		}),
+		On(c, events.ResponseTextDoneEventType, func(ctx context.Context, e events.ResponseTextDone) {
+			fmt.Printf("\n\n")
+		}),
+		On(c, events.ResponseAudioDeltaEventType, func(ctx context.Context, e events.ResponseAudioDelta) {
+			fmt.Print("-")
+		}),
+		On(c, events.ResponseAudioTranscriptDeltaEventType, func(ctx context.Context, e events.ResponseAudioTranscriptDelta) {
+			fmt.Print(e.Delta)
+		}),
+		On(c, events.ResponseAudioTranscriptDoneEventType, func(ctx context.Context, e events.ResponseAudioTranscriptDone) {
+			fmt.Print("\n\n")
+		}),
+	}
+
+	return func() {
+		for _, unsubscribe := range unsubscribes {
+			unsubscribe()
+		}
+	}
+}
@@ -0,0 +1,386 @@
+package openai
+
+import (
+	"openai-realtime/pkg/openai/events"
+	"sync"
+)
+
+// pcm16 24kHz mono 기준 1ms 당 바이트 수
+const pcm16BytesPerMs = 24000 * 2 / 1000
+
+// ConversationChangeType 대화 변경 종류
+type ConversationChangeType string
+
+const (
+	ConversationItemAdded     ConversationChangeType = "added"
+	ConversationItemUpdated   ConversationChangeType = "updated"
+	ConversationItemTruncated ConversationChangeType = "truncated"
+	ConversationItemDeleted   ConversationChangeType = "deleted"
+)
+
+// ConversationItem 로컬에 유지하는 대화 아이템 상태
+type ConversationItem struct {
+	ID         string
+	Type       string // message, function_call, function_call_output
+	Role       string // user, assistant, system
+	Status     string
+	ResponseID string // 어시스턴트 응답으로 생성된 경우 응답 ID
+
+	Text               string // input_text / text content
+	Transcript         string // 음성 전사 (입력 전사 또는 응답 audio_transcript)
+	TranscriptionError string // 입력 음성 전사 실패 사유
+
+	// 음성 메타데이터
+	HasAudio          bool
+	AudioBytes        int  // 수신한 응답 음성 크기 (pcm16)
+	AudioDurationMs   int  // 응답 음성 길이, 입력 음성의 경우 speech_started~speech_stopped 구간
+	InputAudioStartMs int  // 입력 음성 시작 위치 (입력 버퍼 기준)
+	Truncated         bool // conversation.item.truncated 수신 여부
+	TruncatedAtMs     int  // 잘린 위치 (audio_end_ms)
+
+	// function_call / function_call_output
+	CallID    string
+	Name      string
+	Arguments string
+	Output    string
+}
+
+// ConversationChange 대화 변경 알림
+type ConversationChange struct {
+	Type ConversationChangeType
+	Item ConversationItem // 변경 후 아이템 스냅샷 (삭제 시 삭제 직전 상태)
+}
+
+// Conversation 서버 대화 상태를 아이템 ID 기준으로 미러링합니다.
+// 모든 조회 메서드는 복사본을 반환하므로 호출자가 수정해도 내부 상태에 영향을 주지 않습니다.
+type Conversation struct {
+	mu    sync.RWMutex
+	order []string
+	items map[string]*ConversationItem
+
+	// 아이템 생성 전에 도착한 입력 음성 구간 (speech_started/stopped)
+	pendingInput map[string]*ConversationItem
+
+	observerMu sync.RWMutex
+	observerID uint64
+	observers  map[uint64]func(ConversationChange)
+}
+
+func newConversation() *Conversation {
+	return &Conversation{
+		items:        make(map[string]*ConversationItem),
+		pendingInput: make(map[string]*ConversationItem),
+		observers:    make(map[uint64]func(ConversationChange)),
+	}
+}
+
+// Items 대화 아이템을 순서대로 복사해 반환합니다.
+func (c *Conversation) Items() []ConversationItem {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	items := make([]ConversationItem, 0, len(c.order))
+	for _, id := range c.order {
+		items = append(items, *c.items[id])
+	}
+	return items
+}
+
+// Item ID 로 아이템을 조회합니다.
+func (c *Conversation) Item(id string) (ConversationItem, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	item, ok := c.items[id]
+	if !ok {
+		return ConversationItem{}, false
+	}
+	return *item, true
+}
+
+// Len 대화 아이템 수
+func (c *Conversation) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.order)
+}
+
+// OnChange 대화가 변경될 때마다 호출될 함수를 등록합니다.
+// 수신 goroutine 에서 동기적으로 호출되므로 오래 걸리는 작업은 별도 goroutine 에서 처리해야 합니다.
+func (c *Conversation) OnChange(fn func(change ConversationChange)) (unsubscribe func()) {
+	c.observerMu.Lock()
+	defer c.observerMu.Unlock()
+
+	c.observerID++
+	id := c.observerID
+	c.observers[id] = fn
+
+	return func() {
+		c.observerMu.Lock()
+		defer c.observerMu.Unlock()
+		delete(c.observers, id)
+	}
+}
+
+func (c *Conversation) notify(changeType ConversationChangeType, item ConversationItem) {
+	c.observerMu.RLock()
+	observers := make([]func(ConversationChange), 0, len(c.observers))
+	for _, fn := range c.observers {
+		observers = append(observers, fn)
+	}
+	c.observerMu.RUnlock()
+
+	for _, fn := range observers {
+		fn(ConversationChange{Type: changeType, Item: item})
+	}
+}
+
+// update id 아이템에 fn 을 적용하고 변경 알림을 보냅니다.
+// 대화에 없는 아이템(예: conversation 이 "none" 인 응답의 출력)은 무시합니다.
+func (c *Conversation) update(id string, changeType ConversationChangeType, fn func(item *ConversationItem)) {
+	c.mu.Lock()
+	item, ok := c.items[id]
+	if !ok {
+		c.mu.Unlock()
+		return
+	}
+	fn(item)
+	snapshot := *item
+	c.mu.Unlock()
+
+	c.notify(changeType, snapshot)
+}
+
+// insertLocked previousItemID 다음 위치에 새 아이템을 추가합니다. (c.mu 를 잡은 상태에서 호출)
+// previousItemID 가 nil 이면 대화의 맨 앞, 알 수 없는 ID 면 맨 뒤에 추가합니다.
+func (c *Conversation) insertLocked(id string, previousItemID *string) *ConversationItem {
+	item := &ConversationItem{ID: id}
+	if pending, ok := c.pendingInput[id]; ok {
+		item.HasAudio = true
+		item.InputAudioStartMs = pending.InputAudioStartMs
+		item.AudioDurationMs = pending.AudioDurationMs
+		delete(c.pendingInput, id)
+	}
+	c.items[id] = item
+
+	index := len(c.order)
+	if previousItemID != nil {
+		for i, existing := range c.order {
+			if existing == *previousItemID {
+				index = i + 1
+				break
+			}
+		}
+	} else if len(c.order) > 0 {
+		index = 0
+	}
+
+	c.order = append(c.order, "")
+	copy(c.order[index+1:], c.order[index:])
+	c.order[index] = id
+	return item
+}
+
+func (c *Conversation) applyItem(item *ConversationItem, src events.ConversationItem) {
+	item.Type = src.Type
+	if src.Role != "" {
+		item.Role = src.Role
+	}
+	if src.Status != "" {
+		item.Status = src.Status
+	}
+	if src.CallID != "" {
+		item.CallID = src.CallID
+	}
+	if src.Name != "" {
+		item.Name = src.Name
+	}
+	if src.Arguments != "" {
+		item.Arguments = src.Arguments
+	}
+	if src.Output != "" {
+		item.Output = src.Output
+	}
+
+	for _, content := range src.Content {
+		switch content.Type {
+		case "input_text", "text":
+			if content.Text != "" {
+				item.Text = content.Text
+			}
+		case "input_audio", "audio":
+			item.HasAudio = true
+			if content.Transcript != "" {
+				item.Transcript = content.Transcript
+			}
+		}
+	}
+}
+
+// itemCreated conversation.item.created
+func (c *Conversation) itemCreated(e events.ConversationItemCreated) {
+	c.mu.Lock()
+	item, ok := c.items[e.Item.ID]
+	changeType := ConversationItemUpdated
+	if !ok {
+		item = c.insertLocked(e.Item.ID, e.PreviousItemID)
+		changeType = ConversationItemAdded
+	}
+	c.applyItem(item, e.Item)
+	snapshot := *item
+	c.mu.Unlock()
+
+	c.notify(changeType, snapshot)
+}
+
+// outputItem response.output_item.added / response.output_item.done
+func (c *Conversation) outputItem(responseID string, src events.ConversationItem) {
+	c.update(src.ID, ConversationItemUpdated, func(item *ConversationItem) {
+		item.ResponseID = responseID
+		c.applyItem(item, src)
+	})
+}
+
+// speechStarted 생성될 입력 아이템의 음성 시작 위치를 기록합니다.
+func (c *Conversation) speechStarted(e events.InputAudioBufferSpeechStarted) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pendingInput[e.ItemID] = &ConversationItem{InputAudioStartMs: e.AudioStartMs}
+}
+
+// speechStopped 입력 음성 길이를 기록합니다.
+func (c *Conversation) speechStopped(e events.InputAudioBufferSpeechStopped) {
+	c.mu.Lock()
+	if pending, ok := c.pendingInput[e.ItemID]; ok {
+		pending.AudioDurationMs = e.AudioEndMs - pending.InputAudioStartMs
+		c.mu.Unlock()
+		return
+	}
+	c.mu.Unlock()
+
+	c.update(e.ItemID, ConversationItemUpdated, func(item *ConversationItem) {
+		item.AudioDurationMs = e.AudioEndMs - item.InputAudioStartMs
+	})
+}
+
+// clearPendingInput 입력 버퍼가 비워지면 아이템이 생성되지 않으므로 대기 중인 음성 구간을 버립니다.
+func (c *Conversation) clearPendingInput() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pendingInput = make(map[string]*ConversationItem)
+}
+
+func (c *Conversation) appendText(itemID, delta string) {
+	c.update(itemID, ConversationItemUpdated, func(item *ConversationItem) {
+		item.Text += delta
+	})
+}
+
+func (c *Conversation) setText(itemID, text string) {
+	c.update(itemID, ConversationItemUpdated, func(item *ConversationItem) {
+		item.Text = text
+	})
+}
+
+func (c *Conversation) appendTranscript(itemID, delta string) {
+	c.update(itemID, ConversationItemUpdated, func(item *ConversationItem) {
+		item.Transcript += delta
+	})
+}
+
+func (c *Conversation) setTranscript(itemID, transcript string) {
+	c.update(itemID, ConversationItemUpdated, func(item *ConversationItem) {
+		item.Transcript = transcript
+		item.TranscriptionError = ""
+	})
+}
+
+func (c *Conversation) transcriptionFailed(itemID, message string) {
+	c.update(itemID, ConversationItemUpdated, func(item *ConversationItem) {
+		item.TranscriptionError = message
+	})
+}
+
+func (c *Conversation) appendAudio(itemID string, size int) {
+	c.update(itemID, ConversationItemUpdated, func(item *ConversationItem) {
+		item.HasAudio = true
+		item.AudioBytes += size
+		item.AudioDurationMs = item.AudioBytes / pcm16BytesPerMs
+	})
+}
+
+func (c *Conversation) appendArguments(itemID, delta string) {
+	c.update(itemID, ConversationItemUpdated, func(item *ConversationItem) {
+		item.Arguments += delta
+	})
+}
+
+func (c *Conversation) setArguments(itemID, callID, arguments string) {
+	c.update(itemID, ConversationItemUpdated, func(item *ConversationItem) {
+		item.CallID = callID
+		item.Arguments = arguments
+	})
+}
+
+// truncated conversation.item.truncated
+func (c *Conversation) truncated(e events.ConversationItemTruncated) {
+	c.update(e.ItemID, ConversationItemTruncated, func(item *ConversationItem) {
+		item.Truncated = true
+		item.TruncatedAtMs = e.AudioEndMs
+	})
+}
+
+// deleted conversation.item.deleted
+func (c *Conversation) deleted(itemID string) {
+	c.mu.Lock()
+	item, ok := c.items[itemID]
+	if !ok {
+		c.mu.Unlock()
+		return
+	}
+	snapshot := *item
+	delete(c.items, itemID)
+	for i, id := range c.order {
+		if id == itemID {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			break
+		}
+	}
+	c.mu.Unlock()
+
+	c.notify(ConversationItemDeleted, snapshot)
+}
+
+// replayItems 재연결 시 다시 전송할 message 아이템을 텍스트 content 로 변환해 반환합니다.
+func (c *Conversation) replayItems() []events.Item {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	items := make([]events.Item, 0, len(c.order))
+	for _, id := range c.order {
+		item := c.items[id]
+		if item.Type != events.ItemTypeMessage {
+			continue
+		}
+
+		text := item.Text
+		if text == "" {
+			text = item.Transcript
+		}
+		if text == "" {
+			continue
+		}
+
+		contentType := "input_text"
+		if item.Role == "assistant" {
+			contentType = "text"
+		}
+		items = append(items, events.Item{
+			ID:      item.ID,
+			Type:    item.Type,
+			Role:    item.Role,
+			Content: []events.Content{{Type: contentType, Text: text}},
+		})
+	}
+	return items
+}
@@ -0,0 +1,139 @@
+package openai
+
+import (
+	"context"
+	"openai-realtime/pkg/openai/events"
+	"strings"
+	"sync"
+)
+
+// RawHandler 디코딩 전 원본 서버 메시지를 받는 핸들러
+type RawHandler func(ctx context.Context, eventType string, message []byte)
+
+type subscription struct {
+	id     uint64
+	handle func(ctx context.Context, event events.OpenAIEvent)
+}
+
+type rawSubscription struct {
+	id      uint64
+	pattern string
+	handle  RawHandler
+}
+
+// dispatcher 서버 이벤트를 등록된 구독자에게 전달합니다.
+type dispatcher struct {
+	mu     sync.RWMutex
+	nextID uint64
+	typed  map[string][]subscription
+	raw    []rawSubscription
+}
+
+func newDispatcher() *dispatcher {
+	return &dispatcher{
+		typed: make(map[string][]subscription),
+	}
+}
+
+// On eventType 의 서버 이벤트를 타입이 지정된 핸들러로 구독합니다.
+// 반환된 함수를 호출하면 구독이 해제됩니다.
+//
+//	openai.On(client, events.ResponseTextDeltaEventType, func(ctx context.Context, e events.ResponseTextDelta) {
+//		fmt.Print(e.Delta)
+//	})
+func On[T events.OpenAIEvent](c *Client, eventType string, handler func(ctx context.Context, event T)) (unsubscribe func()) {
+	if sample, err := events.DecodeServerEvent(eventType, []byte("{}")); err == nil {
+		if _, ok := sample.(T); !ok {
+			log.Errorf("Handler type %T does not match %s events (%T), it will never be called", *new(T), eventType, sample)
+		}
+	}
+
+	return c.dispatcher.subscribe(eventType, func(ctx context.Context, event events.OpenAIEvent) {
+		if typed, ok := event.(T); ok {
+			handler(ctx, typed)
+		}
+	})
+}
+
+// OnRaw 원본 서버 메시지를 구독합니다.
+// pattern 은 정확한 이벤트 타입, "*" (모든 이벤트) 또는 "response.*" 와 같은 접두어 와일드카드를 사용할 수 있습니다.
+func (c *Client) OnRaw(pattern string, handler RawHandler) (unsubscribe func()) {
+	return c.dispatcher.subscribeRaw(pattern, handler)
+}
+
+func (d *dispatcher) subscribe(eventType string, handle func(ctx context.Context, event events.OpenAIEvent)) func() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.nextID++
+	id := d.nextID
+	d.typed[eventType] = append(d.typed[eventType], subscription{id: id, handle: handle})
+
+	return func() {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+
+		subs := d.typed[eventType]
+		for i, sub := range subs {
+			if sub.id == id {
+				// 디스패치 중인 스냅샷에 영향을 주지 않도록 새 슬라이스를 만듭니다.
+				d.typed[eventType] = append(append([]subscription(nil), subs[:i]...), subs[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+func (d *dispatcher) subscribeRaw(pattern string, handle RawHandler) func() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.nextID++
+	id := d.nextID
+	d.raw = append(d.raw, rawSubscription{id: id, pattern: pattern, handle: handle})
+
+	return func() {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+
+		for i, sub := range d.raw {
+			if sub.id == id {
+				d.raw = append(append([]rawSubscription(nil), d.raw[:i]...), d.raw[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// dispatch 원본 구독자에게 먼저 전달한 뒤 타입 구독자에게 디코딩된 이벤트를 전달합니다.
+// event 가 nil 이면 (모델링되지 않은 이벤트) 원본 구독자에게만 전달됩니다.
+func (d *dispatcher) dispatch(ctx context.Context, eventType string, event events.OpenAIEvent, message []byte) {
+	d.mu.RLock()
+	raw := d.raw
+	typed := d.typed[eventType]
+	d.mu.RUnlock()
+
+	for _, sub := range raw {
+		if matchEventType(sub.pattern, eventType) {
+			sub.handle(ctx, eventType, message)
+		}
+	}
+
+	if event == nil {
+		return
+	}
+	for _, sub := range typed {
+		sub.handle(ctx, event)
+	}
+}
+
+// matchEventType "*" 및 "prefix.*" 형태의 와일드카드를 지원합니다.
+func matchEventType(pattern, eventType string) bool {
+	if pattern == "*" || pattern == eventType {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(eventType, prefix)
+	}
+	return false
+}
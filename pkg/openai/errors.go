@@ -0,0 +1,119 @@
+package openai
+
+import (
+	"fmt"
+	"openai-realtime/pkg/openai/events"
+)
+
+// ErrorClass 서버 오류 분류
+type ErrorClass string
+
+const (
+	ErrorClassRetryable      ErrorClass = "retryable"       // 일시적인 오류, 같은 요청을 나중에 다시 시도할 수 있음
+	ErrorClassInvalidRequest ErrorClass = "invalid-request" // 잘못된 요청, 해당 요청만 실패하고 세션은 유지
+	ErrorClassFatal          ErrorClass = "fatal"           // 세션을 더 이상 사용할 수 없음
+)
+
+// 오류 type 별 분류 (없는 type 은 fatal)
+var errorClassByType = map[string]ErrorClass{
+	"invalid_request_error": ErrorClassInvalidRequest,
+	"server_error":          ErrorClassRetryable,
+	"rate_limit_exceeded":   ErrorClassRetryable,
+	"tokens":                ErrorClassRetryable, // response.done 의 rate limit 오류 type
+	"requests":              ErrorClassRetryable,
+}
+
+// type 과 관계없이 분류가 정해지는 오류 code
+var errorClassByCode = map[string]ErrorClass{
+	"rate_limit_exceeded": ErrorClassRetryable,
+	"session_expired":     ErrorClassFatal,
+	"invalid_api_key":     ErrorClassFatal,
+	"insufficient_quota":  ErrorClassFatal,
+}
+
+// APIError 서버가 보낸 error 이벤트
+// errors.As 로 꺼내 Code 나 Class 로 분기할 수 있습니다.
+type APIError struct {
+	Type    string // 예: invalid_request_error, server_error
+	Code    string // 예: response_cancel_not_active
+	Message string
+	Param   string     // 문제가 된 파라미터 (예: session.voice)
+	EventID string     // 오류를 일으킨 클라이언트 이벤트의 event_id
+	Class   ErrorClass // 분류 (classifyError 참고)
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%s: %s", e.Class, e.Message)
+	if e.Code != "" {
+		msg += " (code: " + e.Code + ")"
+	}
+	if e.Param != "" {
+		msg += " (param: " + e.Param + ")"
+	}
+	if e.EventID != "" {
+		msg += " (event_id: " + e.EventID + ")"
+	}
+	return msg
+}
+
+// Retryable 같은 요청을 다시 시도할 수 있는 오류인지 여부
+func (e *APIError) Retryable() bool {
+	return e.Class == ErrorClassRetryable
+}
+
+// Fatal 세션을 종료해야 하는 오류인지 여부
+func (e *APIError) Fatal() bool {
+	return e.Class == ErrorClassFatal
+}
+
+// ResponseError 상태가 failed 인 response.done
+// Unwrap 으로 원인 APIError 를 꺼낼 수 있습니다.
+type ResponseError struct {
+	ResponseID string
+	Err        *APIError
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("response %s failed: %v", e.ResponseID, e.Err)
+}
+
+func (e *ResponseError) Unwrap() error {
+	return e.Err
+}
+
+// classifyError type 과 code 로 오류를 분류합니다.
+func classifyError(errorType, code string) ErrorClass {
+	if class, ok := errorClassByCode[code]; ok {
+		return class
+	}
+	if class, ok := errorClassByType[errorType]; ok {
+		return class
+	}
+	return ErrorClassFatal
+}
+
+// newAPIError error 이벤트를 APIError 로 변환합니다.
+func newAPIError(e events.EventError) *APIError {
+	return &APIError{
+		Type:    e.Type,
+		Code:    e.Code,
+		Message: e.Message,
+		Param:   e.Param,
+		EventID: e.EventID,
+		Class:   classifyError(e.Type, e.Code),
+	}
+}
+
+// newResponseError 실패한 response.done 을 ResponseError 로 변환합니다.
+func newResponseError(response events.Response) *ResponseError {
+	details := response.StatusDetails.Error
+	return &ResponseError{
+		ResponseID: response.ID,
+		Err: &APIError{
+			Type:    details.Type,
+			Code:    details.Code,
+			Message: details.Message,
+			Class:   classifyError(details.Type, details.Code),
+		},
+	}
+}
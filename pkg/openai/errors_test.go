@@ -0,0 +1,43 @@
+package openai
+
+import (
+	"errors"
+	"openai-realtime/pkg/openai/events"
+	"testing"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		errorType, code string
+		want            ErrorClass
+	}{
+		{"invalid_request_error", "invalid_value", ErrorClassInvalidRequest},
+		{"invalid_request_error", "session_expired", ErrorClassFatal},
+		{"invalid_request_error", "rate_limit_exceeded", ErrorClassRetryable},
+		{"server_error", "", ErrorClassRetryable},
+		{"tokens", "rate_limit_exceeded", ErrorClassRetryable},
+		{"authentication_error", "invalid_api_key", ErrorClassFatal},
+		{"something_new", "", ErrorClassFatal},
+	}
+	for _, tt := range tests {
+		if got := classifyError(tt.errorType, tt.code); got != tt.want {
+			t.Errorf("classifyError(%q, %q) = %s, want %s", tt.errorType, tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestResponseErrorUnwrap(t *testing.T) {
+	var response events.Response
+	response.ID = "resp_1"
+	response.StatusDetails.Error.Type = "server_error"
+	response.StatusDetails.Error.Message = "overloaded"
+
+	var err error = newResponseError(response)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("errors.As(%v) failed", err)
+	}
+	if !apiErr.Retryable() || apiErr.Fatal() {
+		t.Errorf("class = %s, want retryable", apiErr.Class)
+	}
+}
@@ -14,9 +14,14 @@ func (e ClientEvent) GetType() string {
 }
 
 type Item struct {
-	Content []Content `json:"content"`
+	ID      string    `json:"id,omitempty"`
+	Content []Content `json:"content,omitempty"`
 	Type    string    `json:"type"`
-	Role    string    `json:"role"`
+	Role    string    `json:"role,omitempty"`
+
+	// function_call_output 아이템
+	CallID string `json:"call_id,omitempty"`
+	Output string `json:"output,omitempty"`
 }
 
 func (e Item) GetType() string {
@@ -31,13 +36,80 @@ type SessionUpdate struct {
 	InputAudioFormat        string                   `json:"input_audio_format"`
 	OutputAudioFormat       string                   `json:"output_audio_format"`
 	InputAudioTranscription *InputAudioTranscription `json:"input_audio_transcription,omitempty"`
-	TurnDetection           *TurnDetection           `json:"turn_detection,omitempty"`
-	Tools                   []Tool                   `json:"tools"`
+	TurnDetection           *TurnDetection           `json:"turn_detection"` // nil 이면 null 로 전송되어 서버 VAD 를 끔 (클라이언트가 commit, response.create)
+	Tools                   []Tool                   `json:"tools,omitempty"`
 	ToolChoice              string                   `json:"tool_choice"`
 	Temperature             float64                  `json:"temperature"`
-	MaxResponseOutputTokens int                      `json:"max_response_output_tokens,omitempty"`
+	MaxResponseOutputTokens interface{}              `json:"max_response_output_tokens,omitempty"` // 정수 또는 "inf"
 }
 
 func (e SessionUpdate) GetType() string {
 	return "session.update"
 }
+
+// ResponseConfig response.create 시 세션 설정을 덮어쓰는 응답별 설정
+type ResponseConfig struct {
+	Modalities        []string    `json:"modalities,omitempty"`
+	Instructions      string      `json:"instructions,omitempty"`
+	Voice             string      `json:"voice,omitempty"`
+	OutputAudioFormat string      `json:"output_audio_format,omitempty"`
+	Tools             []Tool      `json:"tools,omitempty"`
+	ToolChoice        string      `json:"tool_choice,omitempty"`
+	Temperature       float64     `json:"temperature,omitempty"`
+	MaxOutputTokens   interface{} `json:"max_output_tokens,omitempty"` // 정수 또는 "inf"
+	// Conversation 이 "none" 이면 응답이 기본 대화에 추가되지 않습니다. (기본값 "auto")
+	Conversation string            `json:"conversation,omitempty"`
+	Metadata     map[string]string `json:"metadata,omitempty"`
+	Input        []Item            `json:"input,omitempty"`
+}
+
+type ResponseCreate struct {
+	EventID  string          `json:"event_id,omitempty"`
+	Type     string          `json:"type"`
+	Response *ResponseConfig `json:"response,omitempty"`
+}
+
+func (e ResponseCreate) GetType() string {
+	return e.Type
+}
+
+type ResponseCancel struct {
+	EventID    string `json:"event_id,omitempty"`
+	Type       string `json:"type"`
+	ResponseID string `json:"response_id,omitempty"`
+}
+
+func (e ResponseCancel) GetType() string {
+	return e.Type
+}
+
+type InputAudioBufferClear struct {
+	EventID string `json:"event_id,omitempty"`
+	Type    string `json:"type"`
+}
+
+func (e InputAudioBufferClear) GetType() string {
+	return e.Type
+}
+
+type ConversationItemTruncate struct {
+	EventID      string `json:"event_id,omitempty"`
+	Type         string `json:"type"`
+	ItemID       string `json:"item_id"`
+	ContentIndex int    `json:"content_index"`
+	AudioEndMs   int    `json:"audio_end_ms"`
+}
+
+func (e ConversationItemTruncate) GetType() string {
+	return e.Type
+}
+
+type ConversationItemDelete struct {
+	EventID string `json:"event_id,omitempty"`
+	Type    string `json:"type"`
+	ItemID  string `json:"item_id"`
+}
+
+func (e ConversationItemDelete) GetType() string {
+	return e.Type
+}
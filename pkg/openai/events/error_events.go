@@ -7,3 +7,13 @@ type EventError struct {
 	Param   string `json:"param,omitempty"`
 	EventID string `json:"event_id,omitempty"`
 }
+
+type ErrorEvent struct {
+	EventID string     `json:"event_id"`
+	Type    string     `json:"type"`
+	Error   EventError `json:"error"`
+}
+
+func (e ErrorEvent) GetType() string {
+	return e.Type
+}
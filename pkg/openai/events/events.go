@@ -39,6 +39,16 @@ type SessionCreated struct {
 	MaxResponseOutputTokens interface{}              `json:"max_response_output_tokens,omitempty"`
 }
 
+type SessionCreatedEvent struct {
+	EventID string         `json:"event_id"`
+	Type    string         `json:"type"`
+	Session SessionCreated `json:"session"`
+}
+
+func (e SessionCreatedEvent) GetType() string {
+	return e.Type
+}
+
 type SessionUpdated struct {
 	EventID string `json:"event_id"`
 	Type    string `json:"type"`
@@ -56,6 +66,10 @@ type SessionUpdated struct {
 		Tools                   []interface{}           `json:"tools"`
 		ToolChoice              string                  `json:"tool_choice"`
 		Temperature             float64                 `json:"temperature"`
-		MaxResponseOutputTokens int                     `json:"max_response_output_tokens"`
+		MaxResponseOutputTokens interface{}             `json:"max_response_output_tokens"` // 정수 또는 "inf"
 	} `json:"session"`
 }
+
+func (e SessionUpdated) GetType() string {
+	return e.Type
+}
@@ -1,5 +1,94 @@
 package events
 
+import (
+	"encoding/json"
+	"errors"
+)
+
+// 서버 이벤트 타입
+const (
+	ErrorEventType                                            = "error"
+	SessionCreatedEventType                                   = "session.created"
+	SessionUpdatedEventType                                   = "session.updated"
+	ConversationItemCreatedEventType                          = "conversation.item.created"
+	ConversationItemInputAudioTranscriptionCompletedEventType = "conversation.item.input_audio_transcription.completed"
+	InputAudioBufferCommittedEventType                        = "input_audio_buffer.committed"
+	InputAudioBufferSpeechStartedEventType                    = "input_audio_buffer.speech_started"
+	InputAudioBufferSpeechStoppedEventType                    = "input_audio_buffer.speech_stopped"
+	ResponseCreatedEventType                                  = "response.created"
+	ResponseDoneEventType                                     = "response.done"
+	ResponseOutputItemAddedEventType                          = "response.output_item.added"
+	ResponseOutputItemDoneEventType                           = "response.output_item.done"
+	ResponseContentPartAddedEventType                         = "response.content_part.added"
+	ResponseContentPartDoneEventType                          = "response.content_part.done"
+	ResponseTextDeltaEventType                                = "response.text.delta"
+	ResponseTextDoneEventType                                 = "response.text.done"
+	ResponseAudioTranscriptDeltaEventType                     = "response.audio_transcript.delta"
+	ResponseAudioTranscriptDoneEventType                      = "response.audio_transcript.done"
+	ResponseAudioDeltaEventType                               = "response.audio.delta"
+	ResponseAudioDoneEventType                                = "response.audio.done"
+	RateLimitsUpdatedEventType                                = "rate_limits.updated"
+	ConversationCreatedEventType                              = "conversation.created"
+	ConversationItemInputAudioTranscriptionFailedEventType    = "conversation.item.input_audio_transcription.failed"
+	ConversationItemTruncatedEventType                        = "conversation.item.truncated"
+	ConversationItemDeletedEventType                          = "conversation.item.deleted"
+	InputAudioBufferClearedEventType                          = "input_audio_buffer.cleared"
+	ResponseFunctionCallArgumentsDeltaEventType               = "response.function_call_arguments.delta"
+	ResponseFunctionCallArgumentsDoneEventType                = "response.function_call_arguments.done"
+)
+
+// ErrUnknownEventType 모델링되지 않은 서버 이벤트 타입
+var ErrUnknownEventType = errors.New("unknown server event type")
+
+// serverEventDecoders 서버 이벤트 타입별 디코더
+var serverEventDecoders = map[string]func([]byte) (OpenAIEvent, error){
+	ErrorEventType:                   decode[ErrorEvent],
+	SessionCreatedEventType:          decode[SessionCreatedEvent],
+	SessionUpdatedEventType:          decode[SessionUpdated],
+	ConversationItemCreatedEventType: decode[ConversationItemCreated],
+	ConversationItemInputAudioTranscriptionCompletedEventType: decode[ConversationItemInputAudioTranscriptionCompleted],
+	InputAudioBufferCommittedEventType:                        decode[InputAudioBufferCommitted],
+	InputAudioBufferSpeechStartedEventType:                    decode[InputAudioBufferSpeechStarted],
+	InputAudioBufferSpeechStoppedEventType:                    decode[InputAudioBufferSpeechStopped],
+	ResponseCreatedEventType:                                  decode[ResponseCreated],
+	ResponseDoneEventType:                                     decode[ResponseDone],
+	ResponseOutputItemAddedEventType:                          decode[ResponseOutputItemAdded],
+	ResponseOutputItemDoneEventType:                           decode[ResponseOutputItemDone],
+	ResponseContentPartAddedEventType:                         decode[ResponseContentPartAdded],
+	ResponseContentPartDoneEventType:                          decode[ResponseContentPartDone],
+	ResponseTextDeltaEventType:                                decode[ResponseTextDelta],
+	ResponseTextDoneEventType:                                 decode[ResponseTextDone],
+	ResponseAudioTranscriptDeltaEventType:                     decode[ResponseAudioTranscriptDelta],
+	ResponseAudioTranscriptDoneEventType:                      decode[ResponseAudioTranscriptDone],
+	ResponseAudioDeltaEventType:                               decode[ResponseAudioDelta],
+	ResponseAudioDoneEventType:                                decode[ResponseAudioDone],
+	RateLimitsUpdatedEventType:                                decode[RateLimitsUpdated],
+	ConversationCreatedEventType:                              decode[ConversationCreated],
+	ConversationItemInputAudioTranscriptionFailedEventType:    decode[ConversationItemInputAudioTranscriptionFailed],
+	ConversationItemTruncatedEventType:                        decode[ConversationItemTruncated],
+	ConversationItemDeletedEventType:                          decode[ConversationItemDeleted],
+	InputAudioBufferClearedEventType:                          decode[InputAudioBufferCleared],
+	ResponseFunctionCallArgumentsDeltaEventType:               decode[ResponseFunctionCallArgumentsDelta],
+	ResponseFunctionCallArgumentsDoneEventType:                decode[ResponseFunctionCallArgumentsDone],
+}
+
+// DecodeServerEvent 이벤트 타입에 맞는 구조체로 서버 메시지를 디코딩합니다.
+func DecodeServerEvent(eventType string, message []byte) (OpenAIEvent, error) {
+	decoder, ok := serverEventDecoders[eventType]
+	if !ok {
+		return nil, ErrUnknownEventType
+	}
+	return decoder(message)
+}
+
+func decode[T OpenAIEvent](message []byte) (OpenAIEvent, error) {
+	var event T
+	if err := json.Unmarshal(message, &event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
 // 서버 이벤트 구조체
 type ServerEvent struct {
 	EventID string          `json:"event_id"`
@@ -42,16 +131,9 @@ func (e ResponseContentPartAdded) GetType() string {
 }
 
 type ResponseCreated struct {
-	Type     string `json:"type"`
-	EventID  string `json:"event_id"`
-	Response struct {
-		Object        string        `json:"object"`
-		ID            string        `json:"id"`
-		Status        string        `json:"status"`
-		StatusDetails interface{}   `json:"status_details"`
-		Output        []interface{} `json:"output"`
-		Usage         interface{}   `json:"usage"`
-	} `json:"response"`
+	Type     string   `json:"type"`
+	EventID  string   `json:"event_id"`
+	Response Response `json:"response"`
 }
 
 func (e ResponseCreated) GetType() string {
@@ -74,18 +156,11 @@ func (e RateLimitsUpdated) GetType() string {
 }
 
 type ResponseOutputItemAdded struct {
-	Type        string `json:"type"`
-	EventID     string `json:"event_id"`
-	ResponseID  string `json:"response_id"`
-	OutputIndex int    `json:"output_index"`
-	Item        struct {
-		ID      string        `json:"id"`
-		Object  string        `json:"object"`
-		Type    string        `json:"type"`
-		Status  string        `json:"status"`
-		Role    string        `json:"role"`
-		Content []interface{} `json:"content"`
-	} `json:"item"`
+	Type        string           `json:"type"`
+	EventID     string           `json:"event_id"`
+	ResponseID  string           `json:"response_id"`
+	OutputIndex int              `json:"output_index"`
+	Item        ConversationItem `json:"item"`
 }
 
 func (e ResponseOutputItemAdded) GetType() string {
@@ -104,17 +179,10 @@ func (e InputAudioBufferCommitted) GetType() string {
 }
 
 type ConversationItemCreated struct {
-	Type           string  `json:"type"`
-	EventID        string  `json:"event_id"`
-	PreviousItemID *string `json:"previous_item_id"`
-	Item           struct {
-		ID      string    `json:"id"`
-		Object  string    `json:"object"`
-		Type    string    `json:"type"`
-		Status  string    `json:"status"`
-		Role    string    `json:"role"`
-		Content []Content `json:"content"`
-	} `json:"item"`
+	Type           string           `json:"type"`
+	EventID        string           `json:"event_id"`
+	PreviousItemID *string          `json:"previous_item_id"`
+	Item           ConversationItem `json:"item"`
 }
 
 func (e ConversationItemCreated) GetType() string {
@@ -188,18 +256,11 @@ func (e ResponseContentPartDone) GetType() string {
 }
 
 type ResponseOutputItemDone struct {
-	Type        string `json:"type"`
-	EventID     string `json:"event_id"`
-	ResponseID  string `json:"response_id"`
-	OutputIndex int    `json:"output_index"`
-	Item        struct {
-		ID      string    `json:"id"`
-		Object  string    `json:"object"`
-		Type    string    `json:"type"`
-		Status  string    `json:"status"`
-		Role    string    `json:"role"`
-		Content []Content `json:"content"`
-	} `json:"item"`
+	Type        string           `json:"type"`
+	EventID     string           `json:"event_id"`
+	ResponseID  string           `json:"response_id"`
+	OutputIndex int              `json:"output_index"`
+	Item        ConversationItem `json:"item"`
 }
 
 func (e ResponseOutputItemDone) GetType() string {
@@ -235,40 +296,9 @@ func (e ResponseTextDone) GetType() string {
 }
 
 type ResponseDone struct {
-	Type     string `json:"type"`
-	EventID  string `json:"event_id"`
-	Response struct {
-		ID            string     `json:"id"`
-		Object        string     `json:"object"`
-		Output        []struct{} `json:"output"`
-		Status        string     `json:"status"`
-		StatusDetails struct {
-			Error struct {
-				Code    string `json:"code"`
-				Message string `json:"message"`
-				Type    string `json:"type"`
-			} `json:"error"`
-			Type string `json:"type"`
-		} `json:"status_details"`
-		Usage struct {
-			InputTokenDetails struct {
-				AudioTokens         int `json:"audio_tokens"`
-				CachedTokens        int `json:"cached_tokens"`
-				CachedTokensDetails struct {
-					AudioTokens int `json:"audio_tokens"`
-					TextTokens  int `json:"text_tokens"`
-				} `json:"cached_tokens_details"`
-				TextTokens int `json:"text_tokens"`
-			} `json:"input_token_details"`
-			InputTokens        int `json:"input_tokens"`
-			OutputTokenDetails struct {
-				AudioTokens int `json:"audio_tokens"`
-				TextTokens  int `json:"text_tokens"`
-			} `json:"output_token_details"`
-			OutputTokens int `json:"output_tokens"`
-			TotalTokens  int `json:"total_tokens"`
-		} `json:"usage"`
-	} `json:"response"`
+	Type     string   `json:"type"`
+	EventID  string   `json:"event_id"`
+	Response Response `json:"response"`
 }
 
 func (e ResponseDone) GetType() string {
@@ -304,10 +334,177 @@ func (e ResponseAudioDelta) GetType() string {
 }
 
 type Content struct {
-	Text string `json:"text"`
-	Type string `json:"type"`
+	Text       string `json:"text"`
+	Type       string `json:"type"`
+	Transcript string `json:"transcript,omitempty"`
+	Audio      string `json:"audio,omitempty"`
 }
 
 func (e Content) GetType() string {
 	return e.Type
 }
+
+// 응답 상태 (response.status)
+const (
+	ResponseStatusInProgress = "in_progress"
+	ResponseStatusCompleted  = "completed"
+	ResponseStatusCancelled  = "cancelled"
+	ResponseStatusFailed     = "failed"
+	ResponseStatusIncomplete = "incomplete"
+)
+
+// 응답 취소/미완료 사유 (response.status_details.reason)
+const (
+	ResponseReasonTurnDetected    = "turn_detected"
+	ResponseReasonClientCancelled = "client_cancelled"
+	ResponseReasonMaxOutputTokens = "max_output_tokens"
+	ResponseReasonContentFilter   = "content_filter"
+)
+
+// 아이템 타입
+const (
+	ItemTypeMessage            = "message"
+	ItemTypeFunctionCall       = "function_call"
+	ItemTypeFunctionCallOutput = "function_call_output"
+)
+
+// ConversationItem 서버가 알려주는 대화 아이템 (message, function_call, function_call_output)
+type ConversationItem struct {
+	ID      string    `json:"id"`
+	Object  string    `json:"object"`
+	Type    string    `json:"type"`
+	Status  string    `json:"status"`
+	Role    string    `json:"role,omitempty"`
+	Content []Content `json:"content,omitempty"`
+
+	// function_call / function_call_output 아이템
+	CallID    string `json:"call_id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments,omitempty"`
+	Output    string `json:"output,omitempty"`
+}
+
+// Response response.created / response.done 에 포함되는 응답 객체
+type Response struct {
+	ID            string                `json:"id"`
+	Object        string                `json:"object"`
+	Status        string                `json:"status"`
+	StatusDetails ResponseStatusDetails `json:"status_details"`
+	Output        []ConversationItem    `json:"output"`
+	Usage         Usage                 `json:"usage"`
+}
+
+// ResponseStatusDetails 응답이 취소/미완료/실패한 경우의 상세 정보
+type ResponseStatusDetails struct {
+	Type   string `json:"type"`
+	Reason string `json:"reason,omitempty"`
+	Error  struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+		Type    string `json:"type"`
+	} `json:"error"`
+}
+
+type Usage struct {
+	InputTokenDetails struct {
+		AudioTokens         int `json:"audio_tokens"`
+		CachedTokens        int `json:"cached_tokens"`
+		CachedTokensDetails struct {
+			AudioTokens int `json:"audio_tokens"`
+			TextTokens  int `json:"text_tokens"`
+		} `json:"cached_tokens_details"`
+		TextTokens int `json:"text_tokens"`
+	} `json:"input_token_details"`
+	InputTokens        int `json:"input_tokens"`
+	OutputTokenDetails struct {
+		AudioTokens int `json:"audio_tokens"`
+		TextTokens  int `json:"text_tokens"`
+	} `json:"output_token_details"`
+	OutputTokens int `json:"output_tokens"`
+	TotalTokens  int `json:"total_tokens"`
+}
+
+type ConversationCreated struct {
+	Type         string `json:"type"`
+	EventID      string `json:"event_id"`
+	Conversation struct {
+		ID     string `json:"id"`
+		Object string `json:"object"`
+	} `json:"conversation"`
+}
+
+func (e ConversationCreated) GetType() string {
+	return e.Type
+}
+
+type ConversationItemInputAudioTranscriptionFailed struct {
+	Type         string     `json:"type"`
+	EventID      string     `json:"event_id"`
+	ItemID       string     `json:"item_id"`
+	ContentIndex int        `json:"content_index"`
+	Error        EventError `json:"error"`
+}
+
+func (e ConversationItemInputAudioTranscriptionFailed) GetType() string {
+	return e.Type
+}
+
+type ConversationItemTruncated struct {
+	Type         string `json:"type"`
+	EventID      string `json:"event_id"`
+	ItemID       string `json:"item_id"`
+	ContentIndex int    `json:"content_index"`
+	AudioEndMs   int    `json:"audio_end_ms"`
+}
+
+func (e ConversationItemTruncated) GetType() string {
+	return e.Type
+}
+
+type ConversationItemDeleted struct {
+	Type    string `json:"type"`
+	EventID string `json:"event_id"`
+	ItemID  string `json:"item_id"`
+}
+
+func (e ConversationItemDeleted) GetType() string {
+	return e.Type
+}
+
+type InputAudioBufferCleared struct {
+	Type    string `json:"type"`
+	EventID string `json:"event_id"`
+}
+
+func (e InputAudioBufferCleared) GetType() string {
+	return e.Type
+}
+
+type ResponseFunctionCallArgumentsDelta struct {
+	Type        string `json:"type"`
+	EventID     string `json:"event_id"`
+	ResponseID  string `json:"response_id"`
+	ItemID      string `json:"item_id"`
+	OutputIndex int    `json:"output_index"`
+	CallID      string `json:"call_id"`
+	Delta       string `json:"delta"`
+}
+
+func (e ResponseFunctionCallArgumentsDelta) GetType() string {
+	return e.Type
+}
+
+type ResponseFunctionCallArgumentsDone struct {
+	Type        string `json:"type"`
+	EventID     string `json:"event_id"`
+	ResponseID  string `json:"response_id"`
+	ItemID      string `json:"item_id"`
+	OutputIndex int    `json:"output_index"`
+	CallID      string `json:"call_id"`
+	Name        string `json:"name,omitempty"`
+	Arguments   string `json:"arguments"`
+}
+
+func (e ResponseFunctionCallArgumentsDone) GetType() string {
+	return e.Type
+}
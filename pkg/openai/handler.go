@@ -4,229 +4,250 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/gorilla/websocket"
 	"openai-realtime/pkg/openai/events"
 	"os"
+	"time"
 )
 
 // ReceiveServerEvent 서버 이벤트 수신 (go routine)
+// 연결마다 하나의 수신 goroutine 이 읽은 메시지를 처리하며, 비정상 종료 시 재연결합니다.
+// 종료 시 연결을 닫고 수신 goroutine 이 끝날 때까지 기다린 뒤 cancel 을 호출하며, 종료 사유는 Wait/Err 로 확인합니다.
 func (c *Client) ReceiveServerEvent(ctx context.Context, cancel context.CancelFunc) {
-	defer func() {
-		log.Debug("Closing Server Event Receiver")
-	}()
+	err := c.receiveLoop(ctx)
+	if err != nil {
+		log.Error("Server Event Receiver stopped:", err)
+	}
+
+	reader := c.currentReader()
+	if closeErr := c.Close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	if reader != nil {
+		<-reader.exited
+	}
+
+	cancel()
+	c.finish(err)
+	log.Debug("Closing Server Event Receiver")
+}
 
+// receiveLoop 종료 조건이 될 때까지 메시지를 처리합니다. 정상 종료면 nil 을 반환합니다.
+func (c *Client) receiveLoop(ctx context.Context) error {
 	for {
+		reader := c.currentReader()
+		if reader == nil {
+			return fmt.Errorf("connection is not established")
+		}
 
 		select {
 		case <-ctx.Done():
 			log.Info("Context done. Closing Server Event Receiver")
-			return
-		default:
-			messageChan := make(chan []byte, 10)
-			errorChan := make(chan error)
-
-			go func() {
-				_, message, err := c.readEvent()
-				if err != nil {
-					errorChan <- err
-					return
-				}
-				messageChan <- message
-			}()
-
-			select {
-			case <-ctx.Done():
-				log.Info("Context done. Closing Server Event Receiver")
-				return
-			case err := <-errorChan:
-				if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
-					cancel()
-					log.Warn("Connection closed:", err)
-				} else {
-					log.Error("Read error:", err)
-				}
-				return
-			case message := <-messageChan:
-				var event events.ServerEvent
-				if err := json.Unmarshal(message, &event); err != nil {
-					log.Error("Error unmarshalling server events:", err)
-					continue
+			return nil
+		case in := <-reader.messages:
+			if in.err != nil {
+				if websocket.IsCloseError(in.err, websocket.CloseNormalClosure, websocket.CloseGoingAway) || c.isClosed() {
+					log.Warn("Connection closed:", in.err)
+					return nil
 				}
 
-				if err := c.handleServerEvent(ctx, event, message); err != nil {
-					cancel()
-					return
+				log.Error("Read error:", in.err)
+				if err := c.reconnect(ctx); err != nil {
+					if ctx.Err() != nil {
+						return nil
+					}
+					return err
 				}
+				continue
+			}
+
+			var event events.ServerEvent
+			if err := json.Unmarshal(in.message, &event); err != nil {
+				log.Error("Error unmarshalling server events:", err)
+				continue
+			}
+			c.metricsHook().EventReceived(event.Type)
+
+			if err := c.handleServerEvent(ctx, event, in.message); err != nil {
+				return err
 			}
 		}
 	}
 }
 
 // 서버 이벤트 핸들링 함수
+// 이벤트를 디코딩하여 구독자에게 전달하고, 세션을 종료해야 하는 오류인 경우 error 를 반환합니다.
 func (c *Client) handleServerEvent(ctx context.Context, event events.ServerEvent, message []byte) error {
-	//logEventAsJSON("[RECV]", event, message)
-	switch event.Type {
-	case "error":
-		if event.Error != nil {
-			log.Error("Error:", event.Error.Message)
-			return fmt.Errorf("Error: %s", event.Error.Message)
-		}
-	case "session.created":
-		var sessionCreated events.SessionCreated
-		if err := json.Unmarshal(message, &sessionCreated); err != nil {
-			log.Error("Error unmarshalling session created events:", err)
-			return err
-		}
-		c.status = StatusReady
-	case "session.updated":
-		var sessionUpdated events.SessionUpdated
-		if err := json.Unmarshal(message, &sessionUpdated); err != nil {
-			log.Error("Error unmarshalling session updated events:", err)
-			return err
-		}
-	case "conversation.item.created":
-		var conversationItemCreatedEvent events.ConversationItemCreated
-		if err := json.Unmarshal(message, &conversationItemCreatedEvent); err != nil {
-			log.Error("Error unmarshalling conversation item created events:", err)
-			return err
-		}
-		c.status = StatusReady
-	case "response.text.delta":
-		var responseTextDelta events.ResponseTextDelta
-		if err := json.Unmarshal(message, &responseTextDelta); err != nil {
-			log.Error("Error unmarshalling text delta events:", err)
-			return err
-		}
-		fmt.Print(responseTextDelta.Delta)
-	case "response.text.done":
-		var responseTextDone events.ResponseTextDone
-		if err := json.Unmarshal(message, &responseTextDone); err != nil {
-			log.Error("Error unmarshalling text done events:", err)
-			return err
-		}
-		fmt.Printf("\n\n")
-	case "response.audio.delta":
-		fmt.Print("-")
-		var responseAudioDelta events.ResponseAudioDelta
-		if err := json.Unmarshal(message, &responseAudioDelta); err != nil {
-			log.Error("Error unmarshalling audio delta events:", err)
-			return err
-		}
-		decoded, err := base64.StdEncoding.DecodeString(responseAudioDelta.Delta)
-		if err != nil {
-			log.Error("Error decoding PCM data:", err)
-			return err
+	decoded, err := events.DecodeServerEvent(event.Type, message)
+	if err != nil {
+		if !errors.Is(err, events.ErrUnknownEventType) {
+			// 알려진 이벤트의 형식이 달라도 세션은 유지하고 이 이벤트만 버립니다.
+			log.Errorf("Error unmarshalling %s events: %v", event.Type, err)
+			c.notifyError(fmt.Errorf("decode %s: %w", event.Type, err))
+			return nil
 		}
+		log.Error("Unknown events:", string(message))
+	}
 
-		c.AudioOutputChan <- decoded
-	case "response.audio.done":
-		var audioDoneEvent events.ResponseAudioDone
-		if err := json.Unmarshal(message, &audioDoneEvent); err != nil {
-			log.Error("Error unmarshalling audio done events:", err)
-			return err
-		}
+	c.dispatcher.dispatch(ctx, event.Type, decoded, message)
 
-		c.status = StatusReady
-	case "response.audio_transcript.delta":
-		var audioTranscriptDeltaEvent events.ResponseAudioTranscriptDelta
-		if err := json.Unmarshal(message, &audioTranscriptDeltaEvent); err != nil {
-			log.Error("Error unmarshalling audio transcript delta events:", err)
-			return err
-		}
-		fmt.Print(audioTranscriptDeltaEvent.Delta)
-	case "response.audio_transcript.done":
-		var audioTranscriptDoneEvent events.ResponseAudioTranscriptDone
-		if err := json.Unmarshal(message, &audioTranscriptDoneEvent); err != nil {
-			log.Error("Error unmarshalling audio transcript done events:", err)
-			return err
+	switch e := decoded.(type) {
+	case events.ErrorEvent:
+		apiErr := newAPIError(e.Error)
+		c.metricsHook().Error(apiErr.Class, apiErr.Code)
+		if apiErr.Fatal() {
+			log.Error("Error:", apiErr)
+			return apiErr
 		}
-		fmt.Print("\n\n")
-	case "response.content_part.done":
-		var contentPartDone events.ResponseContentPartDone
-		if err := json.Unmarshal(message, &contentPartDone); err != nil {
-			log.Error("Error unmarshalling content part done events:", err)
-			return err
+		// 잘못된 파라미터나 알 수 없는 call_id 등은 해당 요청만 실패한 것이므로 세션을 유지합니다.
+		log.Warn("Recoverable error:", apiErr)
+		c.notifyError(apiErr)
+	case events.ResponseDone:
+		if e.Response.Status == events.ResponseStatusCancelled || e.Response.Status == events.ResponseStatusIncomplete {
+			log.Infof("Response %s %s: %s", e.Response.ID, e.Response.Status, e.Response.StatusDetails.Reason)
 		}
-	case "response.output_item.done":
-		var outputItemDone events.ResponseOutputItemDone
-		if err := json.Unmarshal(message, &outputItemDone); err != nil {
-			log.Error("Error unmarshalling output item done events:", err)
-			return err
-		}
-	case "response.done":
-		var responseDone events.ResponseDone
-		if err := json.Unmarshal(message, &responseDone); err != nil {
-			log.Error("Error unmarshalling response done events:", err)
-			return err
+		if e.Response.Status == events.ResponseStatusFailed {
+			respErr := newResponseError(e.Response)
+			c.metricsHook().Error(respErr.Err.Class, respErr.Err.Code)
+			if respErr.Err.Fatal() {
+				log.Error("Response failed:", respErr)
+				return respErr
+			}
+			log.Warn("Response failed:", respErr)
+			c.notifyError(respErr)
 		}
+	}
+	return nil
+}
 
-		if responseDone.Response.Status == "failed" {
-			log.Error("Response failed:", responseDone.Response.StatusDetails.Error.Message)
-			return fmt.Errorf("Response failed: %s", responseDone.Response.StatusDetails.Error.Message)
-		}
+// registerCoreHandlers 클라이언트 상태 유지에 필요한 기본 구독자를 등록합니다.
+func (c *Client) registerCoreHandlers() {
+	On(c, events.SessionCreatedEventType, func(ctx context.Context, e events.SessionCreatedEvent) {
+		c.setState(StateReady, e.Type)
+	})
 
-	case "input_audio_buffer.speech_started":
-		var speechStartedEvent events.InputAudioBufferSpeechStarted
-		if err := json.Unmarshal(message, &speechStartedEvent); err != nil {
-			log.Error("Error unmarshalling speech started events:", err)
-			return err
-		}
-	case "input_audio_buffer.speech_stopped":
-		var speechStoppedEvent events.InputAudioBufferSpeechStopped
-		if err := json.Unmarshal(message, &speechStoppedEvent); err != nil {
-			log.Error("Error unmarshalling speech stopped events:", err)
-			return err
-		}
-	case "input_audio_buffer.committed":
-		var audioBufferCommittedEvent events.InputAudioBufferCommitted
-		if err := json.Unmarshal(message, &audioBufferCommittedEvent); err != nil {
-			log.Error("Error unmarshalling input audio buffer committed events:", err)
-			return err
+	On(c, events.ConversationItemCreatedEventType, func(ctx context.Context, e events.ConversationItemCreated) {
+		c.conversation.itemCreated(e)
+		if isUserTextItem(e.Item) {
+			c.latency.committed(time.Now()) // 텍스트 입력은 commit 이 없으므로 메시지가 만들어진 시각부터 측정
 		}
-		//c.status = StatusProcessing
+	})
 
-	case "response.created":
-		var responseCreatedEvent events.ResponseCreated
-		if err := json.Unmarshal(message, &responseCreatedEvent); err != nil {
-			log.Error("Error unmarshalling response created events:", err)
-			return err
-		}
+	On(c, events.InputAudioBufferCommittedEventType, func(ctx context.Context, e events.InputAudioBufferCommitted) {
+		c.latency.committed(time.Now())
+		c.setState(StateAwaitingResponse, e.Type, StateReady, StateUserSpeaking)
+	})
 
-	case "rate_limits.updated":
-		var rateLimitsUpdatedEvent events.RateLimitsUpdated
-		if err := json.Unmarshal(message, &rateLimitsUpdatedEvent); err != nil {
-			log.Error("Error unmarshalling rate limits updated events:", err)
-			return err
-		}
+	On(c, events.ResponseCreatedEventType, func(ctx context.Context, e events.ResponseCreated) {
+		c.latency.responseCreated(e.Response.ID, time.Now())
+		c.setState(StateResponding, e.Type)
+	})
 
-		logEventAsJSON("[RECV]", event, message)
+	On(c, events.ResponseDoneEventType, func(ctx context.Context, e events.ResponseDone) {
+		// barge-in 으로 취소된 경우 사용자가 이미 말하고 있으므로 그대로 둡니다.
+		c.setState(StateReady, e.Type, StateResponding, StateAwaitingResponse)
+		c.latency.responseDone(e.Response.ID)
+		c.metricsHook().TokensUsed(e.Response.Usage)
+	})
 
-	case "response.output_item.added":
-		var responseOutputItemAddedEvent events.ResponseOutputItemAdded
-		if err := json.Unmarshal(message, &responseOutputItemAddedEvent); err != nil {
-			log.Error("Error unmarshalling response output item added events:", err)
-			return err
-		}
+	On(c, events.ResponseOutputItemAddedEventType, func(ctx context.Context, e events.ResponseOutputItemAdded) {
+		c.conversation.outputItem(e.ResponseID, e.Item)
+	})
+
+	On(c, events.ResponseOutputItemDoneEventType, func(ctx context.Context, e events.ResponseOutputItemDone) {
+		c.conversation.outputItem(e.ResponseID, e.Item)
+	})
+
+	On(c, events.ConversationItemInputAudioTranscriptionCompletedEventType, func(ctx context.Context, e events.ConversationItemInputAudioTranscriptionCompleted) {
+		c.conversation.setTranscript(e.ItemID, e.Transcript)
+	})
+
+	On(c, events.ConversationItemInputAudioTranscriptionFailedEventType, func(ctx context.Context, e events.ConversationItemInputAudioTranscriptionFailed) {
+		log.Warnf("Input audio transcription failed for %s: %s", e.ItemID, e.Error.Message)
+		c.conversation.transcriptionFailed(e.ItemID, e.Error.Message)
+	})
+
+	On(c, events.ConversationItemTruncatedEventType, func(ctx context.Context, e events.ConversationItemTruncated) {
+		c.conversation.truncated(e)
+	})
+
+	On(c, events.ConversationItemDeletedEventType, func(ctx context.Context, e events.ConversationItemDeleted) {
+		c.conversation.deleted(e.ItemID)
+	})
+
+	On(c, events.InputAudioBufferSpeechStartedEventType, func(ctx context.Context, e events.InputAudioBufferSpeechStarted) {
+		c.conversation.speechStarted(e)
+		c.setState(StateUserSpeaking, e.Type)
+	})
+
+	On(c, events.InputAudioBufferSpeechStoppedEventType, func(ctx context.Context, e events.InputAudioBufferSpeechStopped) {
+		c.conversation.speechStopped(e)
+		c.latency.SpeechStopped(time.Now())
+		c.setState(StateAwaitingResponse, e.Type, StateUserSpeaking)
+	})
+
+	On(c, events.InputAudioBufferClearedEventType, func(ctx context.Context, e events.InputAudioBufferCleared) {
+		c.conversation.clearPendingInput()
+		c.setState(StateReady, e.Type, StateUserSpeaking)
+	})
+
+	On(c, events.ResponseTextDeltaEventType, func(ctx context.Context, e events.ResponseTextDelta) {
+		c.conversation.appendText(e.ItemID, e.Delta)
+		c.latency.output(e.ResponseID, e.ItemID, false, time.Now())
+	})
 
-	case "response.content_part.added":
-		var contentPartAddedEvent events.ResponseContentPartAdded
-		if err := json.Unmarshal(message, &contentPartAddedEvent); err != nil {
-			log.Error("Error unmarshalling response content part added events:", err)
-			return err
+	On(c, events.ResponseTextDoneEventType, func(ctx context.Context, e events.ResponseTextDone) {
+		c.conversation.setText(e.ItemID, e.Text)
+	})
+
+	On(c, events.ResponseAudioTranscriptDeltaEventType, func(ctx context.Context, e events.ResponseAudioTranscriptDelta) {
+		c.conversation.appendTranscript(e.ItemID, e.Delta)
+	})
+
+	On(c, events.ResponseAudioTranscriptDoneEventType, func(ctx context.Context, e events.ResponseAudioTranscriptDone) {
+		c.conversation.setTranscript(e.ItemID, e.Transcript)
+	})
+
+	On(c, events.ResponseFunctionCallArgumentsDeltaEventType, func(ctx context.Context, e events.ResponseFunctionCallArgumentsDelta) {
+		c.conversation.appendArguments(e.ItemID, e.Delta)
+	})
+
+	On(c, events.ResponseFunctionCallArgumentsDoneEventType, func(ctx context.Context, e events.ResponseFunctionCallArgumentsDone) {
+		c.conversation.setArguments(e.ItemID, e.CallID, e.Arguments)
+	})
+
+	On(c, events.ResponseAudioDeltaEventType, func(ctx context.Context, e events.ResponseAudioDelta) {
+		decoded, err := base64.StdEncoding.DecodeString(e.Delta)
+		if err != nil {
+			log.Error("Error decoding PCM data:", err)
+			return
 		}
-	case "conversation.item.input_audio_transcription.completed":
-		var conversationItemInputAudioTranscriptionCompleted events.ConversationItemInputAudioTranscriptionCompleted
-		if err := json.Unmarshal(message, &conversationItemInputAudioTranscriptionCompleted); err != nil {
-			log.Error("Error unmarshalling conversation item input audio transcription completed events:", err)
-			return err
+		c.conversation.appendAudio(e.ItemID, len(decoded))
+		c.metricsHook().AudioReceived(len(decoded))
+		c.latency.output(e.ResponseID, e.ItemID, true, time.Now())
+		if c.isAudioMuted(e.ItemID) {
+			return
 		}
-	default:
-		stringMessage := string(message)
-		log.Error("Unknown events:", stringMessage)
-	}
 
-	return nil
+		select {
+		case c.AudioOutputChan <- AudioChunk{
+			ResponseID:   e.ResponseID,
+			ItemID:       e.ItemID,
+			ContentIndex: e.ContentIndex,
+			Data:         decoded,
+		}:
+		case <-ctx.Done():
+		}
+	})
+
+	On(c, events.RateLimitsUpdatedEventType, func(ctx context.Context, e events.RateLimitsUpdated) {
+		c.limiter.update(e)
+		for _, limit := range e.RateLimits {
+			log.Debugf("Rate limit %s: %d/%d remaining, resets in %.1fs", limit.Name, limit.Remaining, limit.Limit, limit.ResetSeconds)
+		}
+	})
 }
 
 // 외부네어 변환 할 예정 ffmpeg -f s16le -ar 24000 -ac 1 -i input.pcm output.wav
@@ -0,0 +1,307 @@
+package openai
+
+import (
+	"fmt"
+	"openai-realtime/pkg/openai/events"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	maxTrackedTurns     = 100              // 통계에 사용할 최근 턴 수
+	playbackWaitTimeout = 30 * time.Second // 재생되지 않은 음성(barge-in 등)의 턴을 버리기까지의 시간
+)
+
+// TurnTiming 사용자 입력이 끝난 뒤 응답이 들리기까지 한 턴의 단계별 시각
+// 해당 단계가 없으면 zero 입니다. (예: 클라이언트 commit 모드에서 speech_stopped, 텍스트 응답의 PlayedAt)
+type TurnTiming struct {
+	ResponseID string
+	ItemID     string
+
+	SpeechStoppedAt   time.Time // input_audio_buffer.speech_stopped (또는 SpeechStopped 호출)
+	CommittedAt       time.Time // input_audio_buffer.committed 또는 사용자 텍스트 아이템 생성
+	ResponseCreatedAt time.Time // response.created
+	FirstOutputAt     time.Time // 첫 response.audio.delta 또는 response.text.delta
+	PlayedAt          time.Time // 첫 샘플이 스피커에서 재생된 시각 (PlaybackStarted)
+}
+
+// start 턴의 시작 시각 (speech_stopped, 없으면 committed)
+func (t TurnTiming) start() time.Time {
+	if !t.SpeechStoppedAt.IsZero() {
+		return t.SpeechStoppedAt
+	}
+	return t.CommittedAt
+}
+
+// end 턴의 끝 시각 (스피커 재생, 재생을 추적하지 않으면 첫 출력)
+func (t TurnTiming) end() time.Time {
+	if !t.PlayedAt.IsZero() {
+		return t.PlayedAt
+	}
+	return t.FirstOutputAt
+}
+
+// TurnBreakdown 단계별 소요 시간
+type TurnBreakdown struct {
+	Commit      time.Duration // speech_stopped -> committed
+	Response    time.Duration // committed -> response.created
+	FirstOutput time.Duration // response.created -> 첫 출력 delta
+	Playback    time.Duration // 첫 출력 delta -> 스피커 재생
+	Total       time.Duration // 턴 시작 -> 턴 끝
+}
+
+// Breakdown 단계별 소요 시간 (측정되지 않은 단계는 0)
+func (t TurnTiming) Breakdown() TurnBreakdown {
+	return TurnBreakdown{
+		Commit:      between(t.SpeechStoppedAt, t.CommittedAt),
+		Response:    between(t.CommittedAt, t.ResponseCreatedAt),
+		FirstOutput: between(t.ResponseCreatedAt, t.FirstOutputAt),
+		Playback:    between(t.FirstOutputAt, t.PlayedAt),
+		Total:       between(t.start(), t.end()),
+	}
+}
+
+func (b TurnBreakdown) String() string {
+	return fmt.Sprintf("total %s (commit %s, response %s, first output %s, playback %s)",
+		b.Total.Round(time.Millisecond), b.Commit.Round(time.Millisecond), b.Response.Round(time.Millisecond),
+		b.FirstOutput.Round(time.Millisecond), b.Playback.Round(time.Millisecond))
+}
+
+func between(from, to time.Time) time.Duration {
+	if from.IsZero() || to.IsZero() || to.Before(from) {
+		return 0
+	}
+	return to.Sub(from)
+}
+
+// LatencyStats 한 단계의 요약 통계
+type LatencyStats struct {
+	Count int
+	P50   time.Duration
+	P95   time.Duration
+	Max   time.Duration
+}
+
+func (s LatencyStats) String() string {
+	return fmt.Sprintf("p50 %s, p95 %s, max %s", s.P50.Round(time.Millisecond), s.P95.Round(time.Millisecond), s.Max.Round(time.Millisecond))
+}
+
+// LatencySummary 최근 턴들의 단계별 요약 통계
+type LatencySummary struct {
+	Turns       int
+	Commit      LatencyStats
+	Response    LatencyStats
+	FirstOutput LatencyStats
+	Playback    LatencyStats
+	Total       LatencyStats
+}
+
+// LatencyTracker 서버 이벤트(와 스피커 재생 시각)로 턴별 지연 시간을 측정합니다.
+// 클라이언트마다 하나씩 있으며 (Client.Latency), 완료된 턴의 Total 은 Metrics.TurnLatency 로 보고됩니다.
+type LatencyTracker struct {
+	mu           sync.Mutex
+	waitPlayback bool                   // 음성 턴을 PlaybackStarted 까지 측정
+	pending      *TurnTiming            // 응답 출력을 기다리는 턴
+	playing      map[string]*TurnTiming // 첫 음성을 받아 재생을 기다리는 턴 (itemID)
+	turns        []TurnTiming           // 완료된 최근 턴
+	observer     func(TurnTiming)
+
+	report func(TurnTiming) // 턴이 끝날 때마다 호출 (계측)
+}
+
+func newLatencyTracker(report func(TurnTiming)) *LatencyTracker {
+	return &LatencyTracker{playing: make(map[string]*TurnTiming), report: report}
+}
+
+// Latency 턴 지연 시간 측정기
+func (c *Client) Latency() *LatencyTracker {
+	return c.latency
+}
+
+// WaitForPlayback 음성 턴을 첫 audio delta 가 아닌 스피커 재생 시각(PlaybackStarted)까지 측정합니다.
+// 재생 시각을 PlaybackStarted 로 알려주는 경우에만 호출해야 합니다.
+func (t *LatencyTracker) WaitForPlayback() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.waitPlayback = true
+}
+
+// OnTurn 턴 측정이 끝날 때마다 호출될 함수를 등록합니다. (nil 이면 해제)
+func (t *LatencyTracker) OnTurn(fn func(turn TurnTiming)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.observer = fn
+}
+
+// SpeechStopped 사용자가 말을 멈췄음을 기록하고 새 턴을 시작합니다.
+// 서버 VAD 를 쓰지 않는 경우(클라이언트 끝점 검출) 직접 호출합니다.
+func (t *LatencyTracker) SpeechStopped(at time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending = &TurnTiming{SpeechStoppedAt: at}
+}
+
+// committed 입력이 commit 되었거나 사용자 텍스트 아이템이 만들어진 시각을 기록합니다.
+func (t *LatencyTracker) committed(at time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.pending == nil || !t.pending.CommittedAt.IsZero() {
+		t.pending = &TurnTiming{} // speech_stopped 없이 commit 하는 경우 (push-to-talk, 텍스트 입력 등)
+	}
+	t.pending.CommittedAt = at
+}
+
+func (t *LatencyTracker) responseCreated(responseID string, at time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.pending == nil || t.pending.CommittedAt.IsZero() || t.pending.ResponseID != "" {
+		return // 사용자 입력 없이 만들어진 응답 (첫 인사, 도구 후속 응답 등)
+	}
+	t.pending.ResponseID = responseID
+	t.pending.ResponseCreatedAt = at
+}
+
+// output 응답의 출력 delta 를 받았을 때 호출합니다.
+// 음성이고 재생을 추적하면 재생을 기다리고, 아니면 턴을 끝냅니다.
+func (t *LatencyTracker) output(responseID, itemID string, audio bool, at time.Time) {
+	t.mu.Lock()
+	turn := t.pending
+	if turn == nil || turn.ResponseID != responseID {
+		t.mu.Unlock()
+		return
+	}
+	t.pending = nil
+	turn.ItemID = itemID
+	turn.FirstOutputAt = at
+
+	if audio && t.waitPlayback {
+		for id, playing := range t.playing {
+			if at.Sub(playing.FirstOutputAt) > playbackWaitTimeout {
+				delete(t.playing, id)
+			}
+		}
+		t.playing[itemID] = turn
+		t.mu.Unlock()
+		return
+	}
+	t.finish(turn)
+}
+
+func (t *LatencyTracker) responseDone(responseID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.pending != nil && t.pending.ResponseID == responseID {
+		t.pending = nil // 출력 없이 끝난 응답 (취소, 함수 호출)
+	}
+}
+
+// PlaybackStarted itemID 의 첫 샘플이 at 에 스피커에서 재생되었음을 알려줍니다.
+// 오디오 콜백에서 호출해도 되도록 로그, 계측, 관찰자 호출은 별도 goroutine 에서 처리합니다.
+func (t *LatencyTracker) PlaybackStarted(itemID string, at time.Time) {
+	t.mu.Lock()
+	turn, ok := t.playing[itemID]
+	if !ok {
+		t.mu.Unlock()
+		return
+	}
+	delete(t.playing, itemID)
+	turn.PlayedAt = at
+	t.finish(turn)
+}
+
+// finish 완료된 턴을 기록하고 알립니다. t.mu 를 잡은 상태로 호출하며, 반환 전에 풀어 줍니다.
+func (t *LatencyTracker) finish(turn *TurnTiming) {
+	t.turns = append(t.turns, *turn)
+	if len(t.turns) > maxTrackedTurns {
+		t.turns = t.turns[1:]
+	}
+	observer := t.observer
+	completed := *turn
+	t.mu.Unlock()
+
+	go func() {
+		log.Infof("Turn latency: %s", completed.Breakdown())
+		if t.report != nil {
+			t.report(completed)
+		}
+		if observer != nil {
+			observer(completed)
+		}
+	}()
+}
+
+// Turns 측정이 끝난 최근 턴 (오래된 순)
+func (t *LatencyTracker) Turns() []TurnTiming {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]TurnTiming(nil), t.turns...)
+}
+
+// Summary 최근 턴들의 단계별 p50/p95
+func (t *LatencyTracker) Summary() LatencySummary {
+	turns := t.Turns()
+	stages := make([][]time.Duration, 5)
+	for _, turn := range turns {
+		b := turn.Breakdown()
+		for i, d := range []time.Duration{b.Commit, b.Response, b.FirstOutput, b.Playback, b.Total} {
+			if d > 0 {
+				stages[i] = append(stages[i], d)
+			}
+		}
+	}
+	return LatencySummary{
+		Turns:       len(turns),
+		Commit:      newLatencyStats(stages[0]),
+		Response:    newLatencyStats(stages[1]),
+		FirstOutput: newLatencyStats(stages[2]),
+		Playback:    newLatencyStats(stages[3]),
+		Total:       newLatencyStats(stages[4]),
+	}
+}
+
+func newLatencyStats(values []time.Duration) LatencyStats {
+	if len(values) == 0 {
+		return LatencyStats{}
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+	return LatencyStats{
+		Count: len(values),
+		P50:   percentile(values, 50),
+		P95:   percentile(values, 95),
+		Max:   values[len(values)-1],
+	}
+}
+
+// percentile 정렬된 values 의 p 백분위수 (nearest-rank)
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100 // ceil(p/100 * n)
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// LogSummary 요약 통계를 로그로 출력합니다.
+func (t *LatencyTracker) LogSummary() {
+	s := t.Summary()
+	if s.Turns == 0 {
+		log.Info("Turn latency: no completed turns")
+		return
+	}
+	log.Infof("Turn latency over %d turns: total %s", s.Turns, s.Total)
+	log.Infof("  commit %s; response %s; first output %s; playback %s", s.Commit, s.Response, s.FirstOutput, s.Playback)
+}
+
+// isUserTextItem 사용자가 텍스트로 보낸 메시지인지 여부
+func isUserTextItem(item events.ConversationItem) bool {
+	if item.Role != "user" {
+		return false
+	}
+	for _, content := range item.Content {
+		if content.Type == "input_text" {
+			return true
+		}
+	}
+	return false
+}
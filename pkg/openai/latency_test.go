@@ -0,0 +1,104 @@
+package openai
+
+import (
+	"context"
+	"openai-realtime/pkg/openai/events"
+	"openai-realtime/pkg/openai/openaitest"
+	"testing"
+	"time"
+)
+
+// latencyRecorder TurnLatency 만 기록하는 계측 구현
+type latencyRecorder struct {
+	noopMetrics
+	latencies chan time.Duration
+}
+
+func (r latencyRecorder) TurnLatency(latency time.Duration) {
+	r.latencies <- latency
+}
+
+func TestLatencyTextTurn(t *testing.T) {
+	c, srv := newTestClient(t)
+	recorder := latencyRecorder{latencies: make(chan time.Duration, 1)}
+	c.SetMetrics(recorder)
+	turns := make(chan TurnTiming, 1)
+	c.Latency().OnTurn(func(turn TurnTiming) { turns <- turn })
+
+	srv.Script(openaitest.Response{Text: "hi there"})
+	if err := c.ConversationItemCreate("hello", "user"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ResponseCreate(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	turn := receive(t, turns, "text turn")
+	if turn.CommittedAt.IsZero() || turn.FirstOutputAt.IsZero() || !turn.PlayedAt.IsZero() {
+		t.Errorf("turn = %+v, want committed and first output without playback", turn)
+	}
+	if got := receive(t, recorder.latencies, "TurnLatency"); got != turn.Breakdown().Total {
+		t.Errorf("TurnLatency(%s), want total %s", got, turn.Breakdown().Total)
+	}
+}
+
+func TestLatencyAudioTurnWaitsForPlayback(t *testing.T) {
+	c, srv := newTestClient(t)
+	recorder := latencyRecorder{latencies: make(chan time.Duration, 1)}
+	c.SetMetrics(recorder)
+	c.Latency().WaitForPlayback()
+	turns := make(chan TurnTiming, 1)
+	c.Latency().OnTurn(func(turn TurnTiming) { turns <- turn })
+
+	stopped := make(chan struct{}, 1)
+	On(c, events.InputAudioBufferSpeechStoppedEventType, func(ctx context.Context, e events.InputAudioBufferSpeechStopped) {
+		stopped <- struct{}{}
+	})
+	srv.Emit(map[string]interface{}{"type": events.InputAudioBufferSpeechStoppedEventType, "audio_end_ms": 1000, "item_id": "item_user"})
+	receive(t, stopped, "speech_stopped")
+
+	srv.Script(openaitest.Response{Audio: make([]byte, 4800), Transcript: "hi"})
+	if err := c.SendInputAudioBufferAppend(make([]byte, 4800)); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SendInputAudioBufferCommit(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ResponseCreate(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	chunk := receive(t, c.AudioOutputChan, "audio chunk")
+	select {
+	case turn := <-turns:
+		t.Fatalf("turn finished before playback: %+v", turn)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	playedAt := time.Now()
+	c.Latency().PlaybackStarted(chunk.ItemID, playedAt)
+	turn := receive(t, turns, "audio turn")
+	if !turn.PlayedAt.Equal(playedAt) || turn.SpeechStoppedAt.IsZero() {
+		t.Errorf("turn = %+v, want speech stopped and played at %s", turn, playedAt)
+	}
+	b := turn.Breakdown()
+	if b.Total != playedAt.Sub(turn.SpeechStoppedAt) || b.Playback <= 0 {
+		t.Errorf("breakdown = %s, want total from speech stopped to playback", b)
+	}
+	if got := receive(t, recorder.latencies, "TurnLatency"); got != b.Total {
+		t.Errorf("TurnLatency(%s), want total %s", got, b.Total)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	values := []time.Duration{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	if got := percentile(values, 50); got != 5 {
+		t.Errorf("p50 = %d, want 5", got)
+	}
+	if got := percentile(values, 95); got != 10 {
+		t.Errorf("p95 = %d, want 10", got)
+	}
+	if got := percentile(values[:1], 50); got != 1 {
+		t.Errorf("p50 of one value = %d, want 1", got)
+	}
+}
@@ -0,0 +1,55 @@
+package openai
+
+import (
+	"openai-realtime/pkg/openai/events"
+	"time"
+)
+
+// Metrics 클라이언트 계측 지점 (SetMetrics 로 등록)
+// 수신/전송 goroutine 에서 동기적으로 호출되므로 빠르게 반환해야 합니다.
+type Metrics interface {
+	EventSent(eventType string)
+	EventReceived(eventType string)
+	AudioSent(bytes int)                 // input_audio_buffer.append 로 보낸 pcm16 크기
+	AudioReceived(bytes int)             // response.audio.delta 로 받은 pcm16 크기
+	Reconnect(state string)              // ReconnectEvent.State
+	Error(class ErrorClass, code string) // 서버 error 이벤트와 실패한 response.done
+	TokensUsed(usage events.Usage)       // response.done 의 사용량
+	TurnLatency(latency time.Duration)   // 사용자 입력이 끝난 뒤 응답이 들리기까지 (TurnBreakdown.Total)
+	StateChanged(from, to State)         // 세션 상태 전이 (SetMetrics 시 현재 상태는 from 이 빈 값)
+}
+
+// noopMetrics 계측을 사용하지 않을 때의 기본값
+type noopMetrics struct{}
+
+func (noopMetrics) EventSent(string)          {}
+func (noopMetrics) EventReceived(string)      {}
+func (noopMetrics) AudioSent(int)             {}
+func (noopMetrics) AudioReceived(int)         {}
+func (noopMetrics) Reconnect(string)          {}
+func (noopMetrics) Error(ErrorClass, string)  {}
+func (noopMetrics) TokensUsed(events.Usage)   {}
+func (noopMetrics) TurnLatency(time.Duration) {}
+func (noopMetrics) StateChanged(State, State) {}
+
+// metricsHolder atomic.Value 에 서로 다른 구현을 저장하기 위한 상자
+type metricsHolder struct {
+	Metrics
+}
+
+// SetMetrics 계측 구현을 등록합니다. nil 이면 계측을 끕니다.
+func (c *Client) SetMetrics(m Metrics) {
+	if m == nil {
+		m = noopMetrics{}
+	}
+	c.metrics.Store(metricsHolder{m})
+	m.StateChanged("", c.State())
+}
+
+// metricsHook 현재 계측 구현
+func (c *Client) metricsHook() Metrics {
+	if holder, ok := c.metrics.Load().(metricsHolder); ok {
+		return holder.Metrics
+	}
+	return noopMetrics{}
+}
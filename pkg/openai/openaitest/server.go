@@ -0,0 +1,594 @@
+// Package openaitest 는 OpenAI API 키 없이 openai.Client 를 검증할 수 있도록
+// Realtime beta 프로토콜을 흉내 내는 로컬 WebSocket 서버를 제공합니다.
+package openaitest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/websocket"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ReceivedEvent 서버가 수신한 클라이언트 이벤트
+type ReceivedEvent struct {
+	Type    string
+	EventID string
+	Raw     []byte
+}
+
+// Response 서버가 스트리밍할 스크립트 응답
+type Response struct {
+	Text       string // 텍스트 응답 (Audio 가 없을 때 response.text.* 로 전송)
+	Transcript string // 음성 응답의 전사 (response.audio_transcript.*)
+	Audio      []byte // pcm16 음성 데이터 (response.audio.*)
+	ChunkSize  int    // delta 하나에 담을 최대 바이트/문자 수 (0 이면 기본값)
+	Delay      time.Duration
+
+	// FunctionCall 이 설정된 경우 메시지 대신 function_call 아이템을 스트리밍
+	FunctionCall *FunctionCall
+
+	// Status 가 "failed" 인 경우 response.done 에 ErrorCode/ErrorMessage 를 담아 전송
+	Status       string
+	ErrorCode    string
+	ErrorMessage string
+}
+
+// FunctionCall 스크립트 응답에 포함할 함수 호출
+type FunctionCall struct {
+	Name      string
+	CallID    string // 비어 있으면 자동 생성
+	Arguments string
+}
+
+// Server Realtime API 를 흉내 내는 테스트용 서버
+type Server struct {
+	srv      *httptest.Server
+	upgrader websocket.Upgrader
+
+	mu       sync.Mutex
+	conns    map[*conn]struct{}
+	script   []Response
+	received []ReceivedEvent
+	notify   chan struct{}
+	counter  int
+
+	// APIKey 가 설정된 경우 Authorization 헤더를 검증합니다.
+	APIKey string
+}
+
+// conn 하나의 클라이언트 연결과 해당 세션 상태
+type conn struct {
+	ws      *websocket.Conn
+	writeMu sync.Mutex
+
+	session     map[string]interface{}
+	audioBuffer int
+	lastItemID  string
+}
+
+// NewServer 로컬 주소에서 모의 서버를 시작합니다.
+func NewServer() *Server {
+	s := &Server{
+		conns:  make(map[*conn]struct{}),
+		notify: make(chan struct{}),
+	}
+	s.srv = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// URL openai.NewClient 의 host 로 사용할 수 있는 ws:// 기본 URL
+func (s *Server) URL() string {
+	return "ws://" + strings.TrimPrefix(s.srv.URL, "http://")
+}
+
+// Close 모든 연결과 서버를 종료합니다.
+func (s *Server) Close() {
+	s.Disconnect()
+	s.srv.Close()
+}
+
+// Script 다음 응답 요청 시 순서대로 스트리밍할 응답을 추가합니다.
+func (s *Server) Script(responses ...Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.script = append(s.script, responses...)
+}
+
+// Received 지금까지 수신한 클라이언트 이벤트 목록
+func (s *Server) Received() []ReceivedEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]ReceivedEvent(nil), s.received...)
+}
+
+// WaitFor 지정한 타입의 이벤트가 count 개 이상 수신될 때까지 대기합니다.
+func (s *Server) WaitFor(eventType string, count int, timeout time.Duration) ([]ReceivedEvent, error) {
+	deadline := time.After(timeout)
+	for {
+		s.mu.Lock()
+		var matched []ReceivedEvent
+		for _, event := range s.received {
+			if event.Type == eventType {
+				matched = append(matched, event)
+			}
+		}
+		notify := s.notify
+		s.mu.Unlock()
+
+		if len(matched) >= count {
+			return matched, nil
+		}
+
+		select {
+		case <-notify:
+		case <-deadline:
+			return matched, fmt.Errorf("timed out waiting for %d %s event(s), got %d", count, eventType, len(matched))
+		}
+	}
+}
+
+// Connections 현재 연결된 클라이언트 수
+func (s *Server) Connections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.conns)
+}
+
+// InjectError 연결된 모든 클라이언트에 error 이벤트를 전송합니다.
+func (s *Server) InjectError(errorType, code, message, eventID string) {
+	for _, c := range s.activeConns() {
+		s.emit(c, map[string]interface{}{
+			"type": "error",
+			"error": map[string]interface{}{
+				"type":     errorType,
+				"code":     code,
+				"message":  message,
+				"param":    nil,
+				"event_id": eventID,
+			},
+		})
+	}
+}
+
+// Emit 연결된 모든 클라이언트에 임의의 서버 이벤트를 전송합니다.
+func (s *Server) Emit(event map[string]interface{}) {
+	for _, c := range s.activeConns() {
+		s.emit(c, event)
+	}
+}
+
+// Disconnect close frame 없이 모든 연결을 끊어 비정상 종료를 흉내 냅니다.
+func (s *Server) Disconnect() {
+	s.mu.Lock()
+	conns := s.conns
+	s.conns = make(map[*conn]struct{})
+	s.mu.Unlock()
+
+	for c := range conns {
+		_ = c.ws.Close()
+	}
+}
+
+// CloseNormally close frame 과 함께 모든 연결을 종료합니다.
+func (s *Server) CloseNormally() {
+	for _, c := range s.activeConns() {
+		c.writeMu.Lock()
+		_ = c.ws.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+		c.writeMu.Unlock()
+	}
+}
+
+func (s *Server) activeConns() []*conn {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	conns := make([]*conn, 0, len(s.conns))
+	for c := range s.conns {
+		conns = append(conns, c)
+	}
+	return conns
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	if s.APIKey != "" && r.Header.Get("Authorization") != "Bearer "+s.APIKey {
+		http.Error(w, "invalid api key", http.StatusUnauthorized)
+		return
+	}
+	if r.Header.Get("OpenAI-Beta") != "realtime=v1" {
+		http.Error(w, "missing OpenAI-Beta header", http.StatusBadRequest)
+		return
+	}
+
+	ws, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	c := &conn{
+		ws: ws,
+		session: map[string]interface{}{
+			"id":                  s.nextID("sess"),
+			"object":              "realtime.session",
+			"model":               r.URL.Query().Get("model"),
+			"modalities":          []string{"text", "audio"},
+			"instructions":        "",
+			"voice":               "alloy",
+			"input_audio_format":  "pcm16",
+			"output_audio_format": "pcm16",
+			"turn_detection":      nil,
+			"tools":               []interface{}{},
+			"tool_choice":         "auto",
+			"temperature":         0.8,
+		},
+	}
+
+	s.mu.Lock()
+	s.conns[c] = struct{}{}
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, c)
+		s.mu.Unlock()
+		_ = ws.Close()
+	}()
+
+	s.emit(c, map[string]interface{}{"type": "session.created", "session": c.session})
+
+	for {
+		_, message, err := ws.ReadMessage()
+		if err != nil {
+			return
+		}
+		s.handleClientEvent(c, message)
+	}
+}
+
+func (s *Server) handleClientEvent(c *conn, message []byte) {
+	var event struct {
+		EventID string                 `json:"event_id"`
+		Type    string                 `json:"type"`
+		Session map[string]interface{} `json:"session"`
+		Audio   string                 `json:"audio"`
+		Item    map[string]interface{} `json:"item"`
+		ItemID  string                 `json:"item_id"`
+
+		ContentIndex int `json:"content_index"`
+		AudioEndMs   int `json:"audio_end_ms"`
+	}
+	if err := json.Unmarshal(message, &event); err != nil {
+		s.emitError(c, "invalid_request_error", "invalid_json", err.Error(), "")
+		return
+	}
+	s.record(ReceivedEvent{Type: event.Type, EventID: event.EventID, Raw: message})
+
+	switch event.Type {
+	case "session.update":
+		for k, v := range event.Session {
+			c.session[k] = v
+		}
+		s.emit(c, map[string]interface{}{"type": "session.updated", "session": c.session})
+	case "input_audio_buffer.append":
+		decoded, err := base64.StdEncoding.DecodeString(event.Audio)
+		if err != nil {
+			s.emitError(c, "invalid_request_error", "invalid_value", "audio is not valid base64", event.EventID)
+			return
+		}
+		c.audioBuffer += len(decoded)
+	case "input_audio_buffer.commit":
+		if c.audioBuffer == 0 {
+			s.emitError(c, "invalid_request_error", "input_audio_buffer_commit_empty",
+				"Error committing input audio buffer: the buffer is empty.", event.EventID)
+			return
+		}
+		itemID := s.nextID("item")
+		previous := s.previousItemID(c, itemID)
+		c.audioBuffer = 0
+		s.emit(c, map[string]interface{}{
+			"type":             "input_audio_buffer.committed",
+			"previous_item_id": previous,
+			"item_id":          itemID,
+		})
+		s.emit(c, map[string]interface{}{
+			"type":             "conversation.item.created",
+			"previous_item_id": previous,
+			"item": map[string]interface{}{
+				"id": itemID, "object": "realtime.item", "type": "message", "status": "completed", "role": "user",
+				"content": []interface{}{map[string]interface{}{"type": "input_audio", "transcript": nil}},
+			},
+		})
+		if c.session["turn_detection"] != nil {
+			s.respond(c)
+		}
+	case "conversation.item.create":
+		item := event.Item
+		if item == nil {
+			s.emitError(c, "invalid_request_error", "missing_required_parameter", "Missing required parameter: 'item'.", event.EventID)
+			return
+		}
+		itemID, _ := item["id"].(string)
+		if itemID == "" {
+			itemID = s.nextID("item")
+		}
+		item["id"] = itemID
+		item["object"] = "realtime.item"
+		item["status"] = "completed"
+		s.emit(c, map[string]interface{}{
+			"type":             "conversation.item.created",
+			"previous_item_id": s.previousItemID(c, itemID),
+			"item":             item,
+		})
+	case "input_audio_buffer.clear":
+		c.audioBuffer = 0
+		s.emit(c, map[string]interface{}{"type": "input_audio_buffer.cleared"})
+	case "conversation.item.truncate":
+		s.emit(c, map[string]interface{}{
+			"type":          "conversation.item.truncated",
+			"item_id":       event.ItemID,
+			"content_index": event.ContentIndex,
+			"audio_end_ms":  event.AudioEndMs,
+		})
+	case "conversation.item.delete":
+		s.emit(c, map[string]interface{}{"type": "conversation.item.deleted", "item_id": event.ItemID})
+	case "response.cancel":
+		// 스크립트 응답은 동기적으로 스트리밍되므로 취소할 진행 중인 응답이 없습니다.
+		s.emitError(c, "invalid_request_error", "response_cancel_not_active",
+			"Cancellation failed: no active response found", event.EventID)
+	case "response.create":
+		s.respond(c)
+	}
+}
+
+// respond 스크립트의 다음 응답을 스트리밍합니다. 스크립트가 비어 있으면 빈 응답을 보냅니다.
+func (s *Server) respond(c *conn) {
+	s.mu.Lock()
+	var response Response
+	if len(s.script) > 0 {
+		response = s.script[0]
+		s.script = s.script[1:]
+	}
+	s.mu.Unlock()
+
+	responseID := s.nextID("resp")
+	itemID := s.nextID("item")
+	previous := s.previousItemID(c, itemID)
+	chunk := response.ChunkSize
+	if chunk <= 0 {
+		chunk = 4800
+	}
+
+	base := map[string]interface{}{"response_id": responseID, "item_id": itemID, "output_index": 0, "content_index": 0}
+	with := func(eventType string, fields map[string]interface{}) map[string]interface{} {
+		event := map[string]interface{}{"type": eventType}
+		for k, v := range base {
+			event[k] = v
+		}
+		for k, v := range fields {
+			event[k] = v
+		}
+		return event
+	}
+
+	s.emit(c, map[string]interface{}{
+		"type": "response.created",
+		"response": map[string]interface{}{
+			"id": responseID, "object": "realtime.response", "status": "in_progress", "output": []interface{}{},
+		},
+	})
+
+	if response.FunctionCall != nil {
+		s.respondFunctionCall(c, responseID, itemID, previous, response)
+		return
+	}
+
+	partType := "text"
+	if len(response.Audio) > 0 {
+		partType = "audio"
+	}
+	outputItem := map[string]interface{}{
+		"id": itemID, "object": "realtime.item", "type": "message", "status": "in_progress", "role": "assistant",
+		"content": []interface{}{},
+	}
+	s.emit(c, map[string]interface{}{
+		"type": "response.output_item.added", "response_id": responseID, "output_index": 0, "item": outputItem,
+	})
+	s.emit(c, map[string]interface{}{"type": "conversation.item.created", "previous_item_id": previous, "item": outputItem})
+	s.emit(c, with("response.content_part.added", map[string]interface{}{
+		"part": map[string]interface{}{"type": partType, "transcript": ""},
+	}))
+
+	part := map[string]interface{}{"type": partType}
+	if partType == "audio" {
+		for _, delta := range splitBytes(response.Audio, chunk) {
+			s.pause(response.Delay)
+			s.emit(c, with("response.audio.delta", map[string]interface{}{"delta": base64.StdEncoding.EncodeToString(delta)}))
+		}
+		for _, delta := range splitString(response.Transcript, chunk) {
+			s.emit(c, with("response.audio_transcript.delta", map[string]interface{}{"delta": delta}))
+		}
+		s.emit(c, with("response.audio.done", nil))
+		s.emit(c, with("response.audio_transcript.done", map[string]interface{}{"transcript": response.Transcript}))
+		part["transcript"] = response.Transcript
+	} else {
+		for _, delta := range splitString(response.Text, chunk) {
+			s.pause(response.Delay)
+			s.emit(c, with("response.text.delta", map[string]interface{}{"delta": delta}))
+		}
+		s.emit(c, with("response.text.done", map[string]interface{}{"text": response.Text}))
+		part["text"] = response.Text
+	}
+	s.emit(c, with("response.content_part.done", map[string]interface{}{"part": part}))
+
+	outputItem["status"] = "completed"
+	outputItem["content"] = []interface{}{part}
+	s.emit(c, map[string]interface{}{
+		"type": "response.output_item.done", "response_id": responseID, "output_index": 0, "item": outputItem,
+	})
+
+	s.emitResponseDone(c, responseID, outputItem, response, len(response.Text)+len(response.Transcript), len(response.Audio)/1200)
+}
+
+// respondFunctionCall function_call 아이템과 인자 delta 를 스트리밍합니다.
+func (s *Server) respondFunctionCall(c *conn, responseID, itemID string, previous interface{}, response Response) {
+	call := response.FunctionCall
+	callID := call.CallID
+	if callID == "" {
+		callID = s.nextID("call")
+	}
+
+	outputItem := map[string]interface{}{
+		"id": itemID, "object": "realtime.item", "type": "function_call", "status": "in_progress",
+		"name": call.Name, "call_id": callID, "arguments": "",
+	}
+	s.emit(c, map[string]interface{}{
+		"type": "response.output_item.added", "response_id": responseID, "output_index": 0, "item": outputItem,
+	})
+	s.emit(c, map[string]interface{}{"type": "conversation.item.created", "previous_item_id": previous, "item": outputItem})
+
+	chunk := response.ChunkSize
+	if chunk <= 0 {
+		chunk = 16
+	}
+	for _, delta := range splitString(call.Arguments, chunk) {
+		s.pause(response.Delay)
+		s.emit(c, map[string]interface{}{
+			"type": "response.function_call_arguments.delta", "response_id": responseID, "item_id": itemID,
+			"output_index": 0, "call_id": callID, "delta": delta,
+		})
+	}
+	s.emit(c, map[string]interface{}{
+		"type": "response.function_call_arguments.done", "response_id": responseID, "item_id": itemID,
+		"output_index": 0, "call_id": callID, "arguments": call.Arguments,
+	})
+
+	outputItem["status"] = "completed"
+	outputItem["arguments"] = call.Arguments
+	s.emit(c, map[string]interface{}{
+		"type": "response.output_item.done", "response_id": responseID, "output_index": 0, "item": outputItem,
+	})
+	s.emitResponseDone(c, responseID, outputItem, response, len(call.Arguments), 0)
+}
+
+// emitResponseDone 사용량을 포함한 response.done 을 전송합니다.
+func (s *Server) emitResponseDone(c *conn, responseID string, outputItem map[string]interface{}, response Response, textTokens, audioTokens int) {
+	status := response.Status
+	if status == "" {
+		status = "completed"
+	}
+	var statusDetails interface{}
+	if status == "failed" {
+		statusDetails = map[string]interface{}{
+			"type":  "failed",
+			"error": map[string]interface{}{"type": "server_error", "code": response.ErrorCode, "message": response.ErrorMessage},
+		}
+	}
+	outputTokens := textTokens + audioTokens
+	s.emit(c, map[string]interface{}{
+		"type": "response.done",
+		"response": map[string]interface{}{
+			"id": responseID, "object": "realtime.response", "status": status, "status_details": statusDetails,
+			"output": []interface{}{outputItem},
+			"usage": map[string]interface{}{
+				"total_tokens":  outputTokens,
+				"input_tokens":  0,
+				"output_tokens": outputTokens,
+				"input_token_details": map[string]interface{}{
+					"cached_tokens": 0, "text_tokens": 0, "audio_tokens": 0,
+				},
+				"output_token_details": map[string]interface{}{
+					"text_tokens": textTokens, "audio_tokens": audioTokens,
+				},
+			},
+		},
+	})
+}
+
+func (s *Server) emitError(c *conn, errorType, code, message, eventID string) {
+	s.emit(c, map[string]interface{}{
+		"type": "error",
+		"error": map[string]interface{}{
+			"type": errorType, "code": code, "message": message, "param": nil, "event_id": eventID,
+		},
+	})
+}
+
+// emit event_id 를 채워 서버 이벤트를 전송합니다.
+func (s *Server) emit(c *conn, event map[string]interface{}) {
+	if _, ok := event["event_id"]; !ok {
+		event["event_id"] = s.nextID("event")
+	}
+	message, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_ = c.ws.WriteMessage(websocket.TextMessage, message)
+}
+
+func (s *Server) record(event ReceivedEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.received = append(s.received, event)
+	close(s.notify)
+	s.notify = make(chan struct{})
+}
+
+func (s *Server) nextID(prefix string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.counter++
+	return fmt.Sprintf("%s_%04d", prefix, s.counter)
+}
+
+// previousItemID 연결의 마지막 아이템 ID 를 반환하고 itemID 로 갱신합니다.
+func (s *Server) previousItemID(c *conn, itemID string) interface{} {
+	previous := c.lastItemID
+	c.lastItemID = itemID
+	if previous == "" {
+		return nil
+	}
+	return previous
+}
+
+func (s *Server) pause(d time.Duration) {
+	if d > 0 {
+		time.Sleep(d)
+	}
+}
+
+func splitBytes(data []byte, size int) [][]byte {
+	var chunks [][]byte
+	for len(data) > 0 {
+		n := size
+		if n > len(data) {
+			n = len(data)
+		}
+		chunks = append(chunks, data[:n])
+		data = data[n:]
+	}
+	return chunks
+}
+
+func splitString(text string, size int) []string {
+	runes := []rune(text)
+	var chunks []string
+	for len(runes) > 0 {
+		n := size
+		if n > len(runes) {
+			n = len(runes)
+		}
+		chunks = append(chunks, string(runes[:n]))
+		runes = runes[n:]
+	}
+	return chunks
+}
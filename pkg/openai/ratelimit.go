@@ -0,0 +1,161 @@
+package openai
+
+import (
+	"fmt"
+	"openai-realtime/pkg/openai/events"
+	"sort"
+	"sync"
+	"time"
+)
+
+// rate_limits.updated 의 한도 이름
+const (
+	RateLimitRequests = "requests"
+	RateLimitTokens   = "tokens"
+)
+
+// RateLimit 한도 하나의 남은 양
+type RateLimit struct {
+	Name      string
+	Limit     int
+	Remaining int
+	ResetAt   time.Time // 남은 양이 Limit 으로 돌아오는 시각
+	UpdatedAt time.Time
+}
+
+// RateLimitPolicy response.create 를 보내기 전 남은 한도를 확인하는 기준
+type RateLimitPolicy struct {
+	ReserveRequests int           // 남은 요청 수가 이 값 이하이면 제한
+	ReserveTokens   int           // 남은 토큰 수가 이 값 이하이면 제한 (응답 하나가 쓸 수 있는 양)
+	MaxWait         time.Duration // 초기화까지 이 시간 이내면 기다리고, 더 길면 거부 (0 이면 항상 거부)
+}
+
+// DefaultRateLimitPolicy 기본 제한 기준
+var DefaultRateLimitPolicy = RateLimitPolicy{
+	ReserveRequests: 0,
+	ReserveTokens:   2000,
+	MaxWait:         10 * time.Second,
+}
+
+// RateLimitError 한도에 가까워 요청을 보내지 않았을 때 반환됩니다.
+type RateLimitError struct {
+	Name      string
+	Remaining int
+	ResetAt   time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("rate limit %s nearly exhausted (remaining: %d, resets in %s)",
+		e.Name, e.Remaining, time.Until(e.ResetAt).Round(time.Second))
+}
+
+// rateLimiter 서버가 알려준 남은 한도를 추적하고 response.create 를 지연하거나 거부합니다.
+type rateLimiter struct {
+	mu     sync.Mutex
+	policy RateLimitPolicy
+	limits map[string]RateLimit
+	now    func() time.Time
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{
+		policy: DefaultRateLimitPolicy,
+		limits: make(map[string]RateLimit),
+		now:    time.Now,
+	}
+}
+
+// update rate_limits.updated 로 남은 한도를 갱신합니다.
+func (l *rateLimiter) update(e events.RateLimitsUpdated) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	for _, limit := range e.RateLimits {
+		l.limits[limit.Name] = RateLimit{
+			Name:      limit.Name,
+			Limit:     limit.Limit,
+			Remaining: limit.Remaining,
+			ResetAt:   now.Add(time.Duration(limit.ResetSeconds * float64(time.Second))),
+			UpdatedAt: now,
+		}
+	}
+}
+
+// snapshot 현재 한도 (이름순)
+func (l *rateLimiter) snapshot() []RateLimit {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limits := make([]RateLimit, 0, len(l.limits))
+	for _, limit := range l.limits {
+		limits = append(limits, limit)
+	}
+	sort.Slice(limits, func(i, j int) bool { return limits[i].Name < limits[j].Name })
+	return limits
+}
+
+// check 요청을 보낼 수 있으면 요청 수를 하나 차감하고 0 을 반환합니다.
+// 기다려야 하면 기다릴 시간을, 기다릴 수 없으면 오류를 반환합니다.
+func (l *rateLimiter) check() (time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	var wait time.Duration
+	var exhausted *RateLimitError
+	for name, reserve := range map[string]int{RateLimitRequests: l.policy.ReserveRequests, RateLimitTokens: l.policy.ReserveTokens} {
+		limit, ok := l.limits[name]
+		if !ok || limit.Remaining > reserve || !now.Before(limit.ResetAt) {
+			continue // 정보가 없거나 여유가 있거나 이미 초기화됨
+		}
+		if d := limit.ResetAt.Sub(now); d > wait {
+			wait = d
+			exhausted = &RateLimitError{Name: name, Remaining: limit.Remaining, ResetAt: limit.ResetAt}
+		}
+	}
+
+	if exhausted == nil {
+		// 다음 rate_limits.updated 전에 연달아 보내지 않도록 미리 차감합니다.
+		if limit, ok := l.limits[RateLimitRequests]; ok && limit.Remaining > 0 {
+			limit.Remaining--
+			l.limits[RateLimitRequests] = limit
+		}
+		return 0, nil
+	}
+	if wait > l.policy.MaxWait {
+		return 0, exhausted
+	}
+	return wait, nil
+}
+
+// wait 요청을 보낼 수 있을 때까지 기다립니다.
+func (l *rateLimiter) wait(done <-chan struct{}) error {
+	for {
+		delay, err := l.check()
+		if err != nil || delay == 0 {
+			return err
+		}
+
+		log.Warnf("Rate limit nearly exhausted, delaying request for %s", delay.Round(time.Millisecond))
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-done:
+			timer.Stop()
+			return ErrClientClosed
+		}
+	}
+}
+
+// RateLimits 서버가 마지막으로 알려준 남은 한도 (아직 받지 못했으면 비어 있음)
+func (c *Client) RateLimits() []RateLimit {
+	return c.limiter.snapshot()
+}
+
+// SetRateLimitPolicy response.create 를 제한하는 기준을 바꿉니다.
+func (c *Client) SetRateLimitPolicy(policy RateLimitPolicy) {
+	c.limiter.mu.Lock()
+	defer c.limiter.mu.Unlock()
+	c.limiter.policy = policy
+}
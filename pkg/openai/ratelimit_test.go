@@ -0,0 +1,78 @@
+package openai
+
+import (
+	"encoding/json"
+	"errors"
+	"openai-realtime/pkg/openai/events"
+	"testing"
+	"time"
+)
+
+// newTestLimiter now 시각에 message 의 한도를 받은 rateLimiter
+func newTestLimiter(t *testing.T, now time.Time, message string) *rateLimiter {
+	t.Helper()
+
+	var e events.RateLimitsUpdated
+	if err := json.Unmarshal([]byte(message), &e); err != nil {
+		t.Fatal(err)
+	}
+	l := newRateLimiter()
+	l.now = func() time.Time { return now }
+	l.update(e)
+	return l
+}
+
+func TestRateLimiterCheck(t *testing.T) {
+	now := time.Now()
+
+	t.Run("no limits", func(t *testing.T) {
+		if wait, err := newRateLimiter().check(); wait != 0 || err != nil {
+			t.Errorf("check() = %s, %v, want 0, nil", wait, err)
+		}
+	})
+
+	t.Run("enough budget consumes a request", func(t *testing.T) {
+		l := newTestLimiter(t, now, `{"rate_limits":[{"name":"requests","limit":10,"remaining":2,"reset_seconds":5}]}`)
+		for i := 0; i < 2; i++ {
+			if wait, err := l.check(); wait != 0 || err != nil {
+				t.Fatalf("check() #%d = %s, %v, want 0, nil", i, wait, err)
+			}
+		}
+		if wait, err := l.check(); wait != 5*time.Second || err != nil {
+			t.Errorf("check() = %s, %v, want 5s wait once requests are used up", wait, err)
+		}
+	})
+
+	t.Run("waits for reset within MaxWait", func(t *testing.T) {
+		l := newTestLimiter(t, now, `{"rate_limits":[{"name":"tokens","limit":40000,"remaining":100,"reset_seconds":1.5}]}`)
+		if wait, err := l.check(); wait != 1500*time.Millisecond || err != nil {
+			t.Errorf("check() = %s, %v, want 1.5s", wait, err)
+		}
+	})
+
+	t.Run("rejects beyond MaxWait", func(t *testing.T) {
+		l := newTestLimiter(t, now, `{"rate_limits":[{"name":"tokens","limit":40000,"remaining":100,"reset_seconds":60}]}`)
+		_, err := l.check()
+		var limitErr *RateLimitError
+		if !errors.As(err, &limitErr) || limitErr.Name != RateLimitTokens {
+			t.Errorf("check() error = %v, want *RateLimitError for tokens", err)
+		}
+	})
+
+	t.Run("reset already passed", func(t *testing.T) {
+		l := newTestLimiter(t, now, `{"rate_limits":[{"name":"tokens","limit":40000,"remaining":100,"reset_seconds":1}]}`)
+		l.now = func() time.Time { return now.Add(2 * time.Second) }
+		if wait, err := l.check(); wait != 0 || err != nil {
+			t.Errorf("check() = %s, %v, want 0, nil", wait, err)
+		}
+	})
+}
+
+func TestRateLimiterWaitClosed(t *testing.T) {
+	l := newTestLimiter(t, time.Now(), `{"rate_limits":[{"name":"tokens","limit":40000,"remaining":100,"reset_seconds":5}]}`)
+	done := make(chan struct{})
+	close(done)
+	if err := l.wait(done); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("wait() = %v, want ErrClientClosed", err)
+	}
+}
@@ -0,0 +1,90 @@
+package openai
+
+import (
+	"github.com/gorilla/websocket"
+	"sync"
+)
+
+// 수신 메시지 큐 크기
+const readQueueSize = 64
+
+// inbound 수신한 메시지 또는 읽기 오류
+type inbound struct {
+	message []byte
+	err     error
+}
+
+// connReader 연결 하나에 대해 하나만 실행되는 수신 goroutine
+// 연결이 끊기거나 닫히면 마지막으로 오류를 전달하고 종료하며, 종료되면 exited 가 닫힙니다.
+type connReader struct {
+	messages chan inbound
+	exited   chan struct{}
+	stop     chan struct{} // 연결을 버릴 때 닫힘 (받는 쪽이 없어도 종료하도록)
+	stopOnce sync.Once
+}
+
+// startReader conn 의 수신 goroutine 을 시작합니다. (Connect 에서 호출)
+func (c *Client) startReader(conn *websocket.Conn) *connReader {
+	r := &connReader{
+		messages: make(chan inbound, readQueueSize),
+		exited:   make(chan struct{}),
+		stop:     make(chan struct{}),
+	}
+
+	go func() {
+		defer close(r.exited)
+		for {
+			_, message, err := conn.ReadMessage()
+			select {
+			case r.messages <- inbound{message: message, err: err}:
+			case <-r.stop:
+				return // 버려진 연결이므로 받는 쪽이 없음
+			case <-c.done:
+				return // 클라이언트 종료 후에는 받는 쪽이 없으므로 버림
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return r
+}
+
+// abandon 더 이상 이 연결의 메시지를 받지 않습니다.
+func (r *connReader) abandon() {
+	r.stopOnce.Do(func() {
+		close(r.stop)
+	})
+}
+
+// currentReader 현재 연결의 수신 goroutine
+func (c *Client) currentReader() *connReader {
+	c.connMu.Lock()
+	defer c.connMu.Unlock()
+	return c.reader
+}
+
+// finish 수신 루프 종료 결과를 기록합니다. (처음 한 번만 유효)
+func (c *Client) finish(err error) {
+	c.finishOnce.Do(func() {
+		c.err = err
+		close(c.finished)
+	})
+}
+
+// Wait 수신 루프(ReceiveServerEvent)가 끝나고 연결이 정리될 때까지 기다린 뒤 종료 사유를 반환합니다.
+// 정상 종료(컨텍스트 취소, Close, 서버의 정상 종료)면 nil 입니다.
+func (c *Client) Wait() error {
+	<-c.finished
+	return c.err
+}
+
+// Err 수신 루프가 끝났으면 종료 사유를, 아직 실행 중이거나 정상 종료했으면 nil 을 반환합니다.
+func (c *Client) Err() error {
+	select {
+	case <-c.finished:
+		return c.err
+	default:
+		return nil
+	}
+}
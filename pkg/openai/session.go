@@ -0,0 +1,251 @@
+package openai
+
+import (
+	"errors"
+	"fmt"
+	"openai-realtime/pkg/openai/events"
+	"strings"
+)
+
+const (
+	minTemperature = 0.6
+	maxTemperature = 1.2
+
+	maxResponseOutputTokensLimit = 4096
+	infiniteOutputTokens         = "inf"
+)
+
+var (
+	allowedVoices       = []string{"alloy", "ash", "ballad", "coral", "echo", "sage", "shimmer", "verse"}
+	allowedModalities   = []string{"text", "audio"}
+	allowedAudioFormats = []string{"pcm16", "g711_ulaw", "g711_alaw"}
+	allowedToolChoices  = []string{"auto", "none", "required"}
+	allowedTurnTypes    = []string{"server_vad"}
+)
+
+// SessionConfig session.update 로 전송할 세션 설정
+// NewSessionConfig 에 SessionOption 을 넘겨 만들며, 지정하지 않은 항목은 기본값을 사용합니다.
+type SessionConfig struct {
+	Voice                   string
+	Modalities              []string
+	InputAudioFormat        string
+	OutputAudioFormat       string
+	InputAudioTranscription *events.InputAudioTranscription
+	Temperature             float64
+	MaxResponseOutputTokens int  // InfiniteOutputTokens 가 true 이면 무시
+	InfiniteOutputTokens    bool // max_response_output_tokens 를 "inf" 로 전송
+	Tools                   []events.Tool
+	ToolChoice              string
+	Instructions            string
+	TurnDetection           *events.TurnDetection // 기본값은 서버 VAD, nil 이면 null 을 보내 서버 VAD 를 끔 (WithoutTurnDetection)
+}
+
+// DefaultTurnDetection 서버 VAD 기본 설정
+func DefaultTurnDetection() *events.TurnDetection {
+	return &events.TurnDetection{
+		Type:              "server_vad",
+		Threshold:         0.5,
+		PrefixPaddingMs:   300,
+		SilenceDurationMs: 500,
+	}
+}
+
+// SessionOption 세션 설정 옵션
+type SessionOption func(*SessionConfig)
+
+// NewSessionConfig 기본값에 옵션을 적용한 뒤 검증한 세션 설정을 반환합니다.
+func NewSessionConfig(opts ...SessionOption) (*SessionConfig, error) {
+	config := &SessionConfig{
+		Voice:                   "alloy",
+		Modalities:              []string{"text", "audio"},
+		InputAudioFormat:        "pcm16",
+		OutputAudioFormat:       "pcm16",
+		Temperature:             1.2,
+		MaxResponseOutputTokens: 1024,
+		ToolChoice:              "auto",
+		TurnDetection:           DefaultTurnDetection(),
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// WithVoice 응답 음성
+func WithVoice(voice string) SessionOption {
+	return func(c *SessionConfig) { c.Voice = voice }
+}
+
+// WithModalities 응답 형식 ("text" 또는 "text", "audio")
+func WithModalities(modalities ...string) SessionOption {
+	return func(c *SessionConfig) { c.Modalities = modalities }
+}
+
+// WithInputAudioFormat 입력 음성 형식
+func WithInputAudioFormat(format string) SessionOption {
+	return func(c *SessionConfig) { c.InputAudioFormat = format }
+}
+
+// WithOutputAudioFormat 출력 음성 형식
+func WithOutputAudioFormat(format string) SessionOption {
+	return func(c *SessionConfig) { c.OutputAudioFormat = format }
+}
+
+// WithInputAudioTranscription 입력 음성 전사 모델 (예: "whisper-1")
+func WithInputAudioTranscription(model string) SessionOption {
+	return func(c *SessionConfig) {
+		c.InputAudioTranscription = &events.InputAudioTranscription{Model: model}
+	}
+}
+
+// WithTemperature 샘플링 온도
+func WithTemperature(temperature float64) SessionOption {
+	return func(c *SessionConfig) { c.Temperature = temperature }
+}
+
+// WithMaxResponseOutputTokens 응답 하나의 최대 출력 토큰 수
+func WithMaxResponseOutputTokens(tokens int) SessionOption {
+	return func(c *SessionConfig) {
+		c.MaxResponseOutputTokens = tokens
+		c.InfiniteOutputTokens = false
+	}
+}
+
+// WithInfiniteResponseOutputTokens 최대 출력 토큰 수 제한 없음 ("inf")
+func WithInfiniteResponseOutputTokens() SessionOption {
+	return func(c *SessionConfig) { c.InfiniteOutputTokens = true }
+}
+
+// WithTools 모델이 호출할 수 있는 도구 목록
+func WithTools(tools []events.Tool) SessionOption {
+	return func(c *SessionConfig) { c.Tools = tools }
+}
+
+// WithToolChoice 도구 선택 방식 ("auto", "none", "required")
+func WithToolChoice(choice string) SessionOption {
+	return func(c *SessionConfig) { c.ToolChoice = choice }
+}
+
+// WithInstructions 시스템 지시문
+func WithInstructions(instructions string) SessionOption {
+	return func(c *SessionConfig) { c.Instructions = instructions }
+}
+
+// WithTurnDetection 턴 감지 설정 (nil 이면 WithoutTurnDetection 과 같음)
+func WithTurnDetection(turnDetection *events.TurnDetection) SessionOption {
+	return func(c *SessionConfig) { c.TurnDetection = turnDetection }
+}
+
+// WithoutTurnDetection 서버 VAD 를 끄고 클라이언트가 commit/response.create 로 턴을 관리
+func WithoutTurnDetection() SessionOption {
+	return WithTurnDetection(nil)
+}
+
+// Validate 허용 값과 범위를 검사합니다. 잘못된 항목이 여러 개면 모두 모아 반환합니다.
+func (c *SessionConfig) Validate() error {
+	var errs []error
+	invalid := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("session config: "+format, args...))
+	}
+
+	if !contains(allowedVoices, c.Voice) {
+		invalid("voice %q is not supported (allowed: %s)", c.Voice, strings.Join(allowedVoices, ", "))
+	}
+
+	if len(c.Modalities) == 0 {
+		invalid("modalities must not be empty")
+	}
+	seen := make(map[string]bool, len(c.Modalities))
+	for _, modality := range c.Modalities {
+		if !contains(allowedModalities, modality) {
+			invalid("modality %q is not supported (allowed: %s)", modality, strings.Join(allowedModalities, ", "))
+		} else if seen[modality] {
+			invalid("modality %q is duplicated", modality)
+		}
+		seen[modality] = true
+	}
+	if seen["audio"] && !seen["text"] {
+		invalid("modalities [audio] must also include text")
+	}
+
+	if !contains(allowedAudioFormats, c.InputAudioFormat) {
+		invalid("input_audio_format %q is not supported (allowed: %s)", c.InputAudioFormat, strings.Join(allowedAudioFormats, ", "))
+	}
+	if !contains(allowedAudioFormats, c.OutputAudioFormat) {
+		invalid("output_audio_format %q is not supported (allowed: %s)", c.OutputAudioFormat, strings.Join(allowedAudioFormats, ", "))
+	}
+	if c.InputAudioTranscription != nil && c.InputAudioTranscription.Model == "" {
+		invalid("input_audio_transcription model is required")
+	}
+
+	if c.Temperature < minTemperature || c.Temperature > maxTemperature {
+		invalid("temperature %v is out of range [%v, %v]", c.Temperature, minTemperature, maxTemperature)
+	}
+
+	if !c.InfiniteOutputTokens && (c.MaxResponseOutputTokens < 1 || c.MaxResponseOutputTokens > maxResponseOutputTokensLimit) {
+		invalid("max_response_output_tokens %d is out of range [1, %d] (use %q for no limit)", c.MaxResponseOutputTokens, maxResponseOutputTokensLimit, infiniteOutputTokens)
+	}
+
+	if !contains(allowedToolChoices, c.ToolChoice) {
+		invalid("tool_choice %q is not supported (allowed: %s)", c.ToolChoice, strings.Join(allowedToolChoices, ", "))
+	} else if c.ToolChoice == "required" && len(c.Tools) == 0 {
+		invalid("tool_choice %q requires at least one tool", c.ToolChoice)
+	}
+	for i, tool := range c.Tools {
+		if tool.Name == "" {
+			invalid("tools[%d] name is required", i)
+		}
+	}
+
+	if td := c.TurnDetection; td != nil {
+		if !contains(allowedTurnTypes, td.Type) {
+			invalid("turn_detection type %q is not supported (allowed: %s)", td.Type, strings.Join(allowedTurnTypes, ", "))
+		}
+		if td.Threshold < 0 || td.Threshold > 1 {
+			invalid("turn_detection threshold %v is out of range [0, 1]", td.Threshold)
+		}
+		if td.PrefixPaddingMs < 0 {
+			invalid("turn_detection prefix_padding_ms %d must not be negative", td.PrefixPaddingMs)
+		}
+		if td.SilenceDurationMs < 0 {
+			invalid("turn_detection silence_duration_ms %d must not be negative", td.SilenceDurationMs)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// event session.update 이벤트의 session 필드로 변환합니다.
+func (c *SessionConfig) event() *events.SessionUpdate {
+	var maxTokens interface{} = c.MaxResponseOutputTokens
+	if c.InfiniteOutputTokens {
+		maxTokens = infiniteOutputTokens
+	}
+
+	return &events.SessionUpdate{
+		Modalities:              c.Modalities,
+		Instructions:            c.Instructions,
+		Voice:                   c.Voice,
+		InputAudioFormat:        c.InputAudioFormat,
+		OutputAudioFormat:       c.OutputAudioFormat,
+		InputAudioTranscription: c.InputAudioTranscription,
+		TurnDetection:           c.TurnDetection,
+		Tools:                   c.Tools,
+		ToolChoice:              c.ToolChoice,
+		Temperature:             c.Temperature,
+		MaxResponseOutputTokens: maxTokens,
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package openai
+
+import (
+	"openai-realtime/pkg/openai/events"
+	"strings"
+	"testing"
+)
+
+func TestNewSessionConfigDefaults(t *testing.T) {
+	config, err := NewSessionConfig()
+	if err != nil {
+		t.Fatalf("NewSessionConfig: %v", err)
+	}
+	if config.TurnDetection == nil || config.TurnDetection.Type != "server_vad" {
+		t.Errorf("turn detection = %+v, want server_vad", config.TurnDetection)
+	}
+
+	config, err = NewSessionConfig(WithoutTurnDetection())
+	if err != nil {
+		t.Fatalf("NewSessionConfig: %v", err)
+	}
+	if config.TurnDetection != nil {
+		t.Errorf("turn detection = %+v, want nil", config.TurnDetection)
+	}
+}
+
+func TestSessionConfigValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []SessionOption
+		want []string // 오류 메시지에 포함되어야 하는 내용 (비어 있으면 유효)
+	}{
+		{name: "defaults"},
+		{name: "infinite tokens", opts: []SessionOption{WithMaxResponseOutputTokens(0), WithInfiniteResponseOutputTokens()}},
+		{name: "voice", opts: []SessionOption{WithVoice("robot")}, want: []string{`voice "robot"`}},
+		{name: "audio only", opts: []SessionOption{WithModalities("audio")}, want: []string{"must also include text"}},
+		{name: "duplicated modality", opts: []SessionOption{WithModalities("text", "text")}, want: []string{"duplicated"}},
+		{name: "temperature", opts: []SessionOption{WithTemperature(2)}, want: []string{"temperature 2"}},
+		{name: "max tokens", opts: []SessionOption{WithMaxResponseOutputTokens(5000)}, want: []string{"max_response_output_tokens 5000"}},
+		{name: "required tool", opts: []SessionOption{WithToolChoice("required")}, want: []string{"requires at least one tool"}},
+		{
+			name: "turn detection",
+			opts: []SessionOption{WithTurnDetection(&events.TurnDetection{Type: "semantic", Threshold: 2})},
+			want: []string{`turn_detection type "semantic"`, "threshold 2"},
+		},
+		{
+			name: "collects all errors",
+			opts: []SessionOption{WithVoice("robot"), WithInputAudioFormat("mp3"), WithTemperature(0)},
+			want: []string{"voice", "input_audio_format", "temperature"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewSessionConfig(tt.opts...)
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected error containing %q", tt.want)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q does not contain %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSessionConfigEvent(t *testing.T) {
+	config, err := NewSessionConfig(WithMaxResponseOutputTokens(512))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := config.event().MaxResponseOutputTokens; got != 512 {
+		t.Errorf("max_response_output_tokens = %v, want 512", got)
+	}
+
+	config.InfiniteOutputTokens = true
+	if got := config.event().MaxResponseOutputTokens; got != "inf" {
+		t.Errorf("max_response_output_tokens = %v, want inf", got)
+	}
+}
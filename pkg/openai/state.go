@@ -0,0 +1,156 @@
+package openai
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// State 클라이언트 세션 상태
+type State string
+
+const (
+	StateConnecting       State = "connecting"        // WebSocket 연결 중, session.created 대기
+	StateReady            State = "ready"             // 세션 준비 완료, 대기 중
+	StateUserSpeaking     State = "user-speaking"     // 사용자가 말하는 중 (speech_started)
+	StateAwaitingResponse State = "awaiting-response" // 입력이 commit 되어 응답 대기 중
+	StateResponding       State = "responding"        // 응답 생성 중 (response.created ~ response.done)
+	StateReconnecting     State = "reconnecting"      // 연결이 끊겨 재연결 중
+	StateClosed           State = "closed"            // 연결 종료
+)
+
+// 허용되는 상태 전이 (closed 로의 전이는 항상 허용)
+var stateTransitions = map[State][]State{
+	StateClosed:           {StateConnecting},
+	StateConnecting:       {StateReady, StateReconnecting},
+	StateReady:            {StateUserSpeaking, StateAwaitingResponse, StateResponding, StateReconnecting},
+	StateUserSpeaking:     {StateReady, StateAwaitingResponse, StateResponding, StateReconnecting},
+	StateAwaitingResponse: {StateReady, StateUserSpeaking, StateResponding, StateReconnecting},
+	StateResponding:       {StateReady, StateUserSpeaking, StateReconnecting},
+	StateReconnecting:     {StateConnecting},
+}
+
+// StateChange 상태 전이 알림
+type StateChange struct {
+	From   State
+	To     State
+	Reason string // 전이를 일으킨 이벤트 (예: "input_audio_buffer.speech_started")
+}
+
+// sessionActive 세션이 만들어져 오디오와 이벤트를 보낼 수 있는 상태인지 여부
+func (s State) sessionActive() bool {
+	switch s {
+	case StateReady, StateUserSpeaking, StateAwaitingResponse, StateResponding:
+		return true
+	}
+	return false
+}
+
+// canTransition from 에서 to 로 전이할 수 있는지 여부
+func canTransition(from, to State) bool {
+	if to == StateClosed {
+		return true
+	}
+	for _, allowed := range stateTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// stateMachine 검증된 전이만 허용하고 전이마다 관찰자에게 알립니다.
+// 현재 상태는 잠금 없이 원자적으로 읽을 수 있습니다.
+type stateMachine struct {
+	current atomic.Value // State
+	mu      sync.Mutex   // 전이 직렬화
+
+	observerMu sync.RWMutex
+	observerID uint64
+	observers  map[uint64]func(StateChange)
+}
+
+func newStateMachine() *stateMachine {
+	m := &stateMachine{observers: make(map[uint64]func(StateChange))}
+	m.current.Store(StateClosed)
+	return m
+}
+
+func (m *stateMachine) get() State {
+	return m.current.Load().(State)
+}
+
+// transition to 로 전이합니다. from 이 주어지면 현재 상태가 그 중 하나일 때만 전이하며, 아니면 무시합니다.
+// 같은 상태로의 전이는 알림 없이 무시하고, 허용되지 않는 전이는 오류를 반환합니다.
+func (m *stateMachine) transition(to State, reason string, from ...State) error {
+	m.mu.Lock()
+	current := m.get()
+	if current == to || (len(from) > 0 && !containsState(from, current)) {
+		m.mu.Unlock()
+		return nil
+	}
+	if !canTransition(current, to) {
+		m.mu.Unlock()
+		return fmt.Errorf("invalid state transition %s -> %s (%s)", current, to, reason)
+	}
+	m.current.Store(to)
+	m.mu.Unlock()
+
+	m.notify(StateChange{From: current, To: to, Reason: reason})
+	return nil
+}
+
+func (m *stateMachine) subscribe(fn func(StateChange)) (unsubscribe func()) {
+	m.observerMu.Lock()
+	defer m.observerMu.Unlock()
+
+	m.observerID++
+	id := m.observerID
+	m.observers[id] = fn
+
+	return func() {
+		m.observerMu.Lock()
+		defer m.observerMu.Unlock()
+		delete(m.observers, id)
+	}
+}
+
+func (m *stateMachine) notify(change StateChange) {
+	m.observerMu.RLock()
+	observers := make([]func(StateChange), 0, len(m.observers))
+	for _, fn := range m.observers {
+		observers = append(observers, fn)
+	}
+	m.observerMu.RUnlock()
+
+	for _, fn := range observers {
+		fn(change)
+	}
+}
+
+func containsState(states []State, state State) bool {
+	for _, s := range states {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+// State 현재 상태 (다른 goroutine 에서 안전하게 읽을 수 있음)
+func (c *Client) State() State {
+	return c.state.get()
+}
+
+// OnStateChange 상태가 바뀔 때마다 호출될 함수를 등록합니다.
+// 전이를 일으킨 goroutine 에서 동기적으로 호출되므로 오래 걸리는 작업은 별도 goroutine 에서 처리해야 합니다.
+func (c *Client) OnStateChange(fn func(change StateChange)) (unsubscribe func()) {
+	return c.state.subscribe(fn)
+}
+
+// setState 상태를 전이하고, 허용되지 않는 전이는 경고만 남깁니다.
+func (c *Client) setState(to State, reason string, from ...State) {
+	if err := c.state.transition(to, reason, from...); err != nil {
+		log.Warn(err)
+	}
+}
@@ -37,10 +37,22 @@ func generateEventID() string {
 }
 
 // getUrl WebSocket URL 생성
+// host 에 scheme 이 포함된 경우(예: "ws://127.0.0.1:8080") 해당 scheme 을 사용합니다.
 func (c *Client) getUrl() string {
+	scheme, host := "wss", c.host
+	if base, err := url.Parse(c.host); err == nil && base.Scheme != "" && base.Host != "" {
+		host = base.Host
+		switch base.Scheme {
+		case "http", "ws":
+			scheme = "ws"
+		default:
+			scheme = "wss"
+		}
+	}
+
 	u := url.URL{
-		Scheme:   "wss",
-		Host:     c.host,
+		Scheme:   scheme,
+		Host:     host,
 		Path:     c.path,
 		RawQuery: "model=" + url.QueryEscape(c.model),
 	}
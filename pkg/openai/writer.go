@@ -0,0 +1,121 @@
+package openai
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	controlQueueSize = 32  // response.cancel 등 우선 전송 이벤트 큐 크기
+	normalQueueSize  = 256 // 일반 이벤트 및 오디오 append 큐 크기
+)
+
+var (
+	// ErrSendQueueFull 전송 큐가 가득 차 이벤트를 보낼 수 없을 때 반환됩니다. (backpressure)
+	ErrSendQueueFull = errors.New("send queue is full")
+	// ErrClientClosed 클라이언트가 종료된 뒤 전송을 시도할 때 반환됩니다.
+	ErrClientClosed = errors.New("client is closed")
+)
+
+// outbound 전송 대기 중인 이벤트
+type outbound struct {
+	eventType string
+	message   []byte
+	result    chan error // nil 이면 전송 결과를 기다리지 않음
+}
+
+// priorityEventTypes 대기 중인 오디오보다 먼저 전송해야 하는 제어 이벤트
+var priorityEventTypes = map[string]bool{
+	ResponseCancelEventType:           true,
+	ConversationItemTruncateEventType: true,
+}
+
+// enqueue 이벤트를 우선순위에 맞는 큐에 넣습니다. 큐가 가득 차면 ErrSendQueueFull 을 반환합니다.
+func (c *Client) enqueue(msg outbound) error {
+	queue := c.normalQueue
+	if priorityEventTypes[msg.eventType] {
+		queue = c.controlQueue
+	}
+
+	select {
+	case <-c.done:
+		return ErrClientClosed
+	default:
+	}
+
+	select {
+	case queue <- msg:
+		return nil
+	default:
+		return fmt.Errorf("%w: dropping %s", ErrSendQueueFull, msg.eventType)
+	}
+}
+
+// writeLoop 모든 이벤트를 하나의 goroutine 에서 순서대로 전송합니다. (go routine)
+// gorilla/websocket 은 동시 writer 를 허용하지 않으므로 모든 전송은 이 루프를 거칩니다. (close 프레임은 루프가 끝난 뒤 Close 에서 전송)
+func (c *Client) writeLoop() {
+	defer func() {
+		close(c.writerDone)
+		log.Debug("Closing Client Event Writer")
+	}()
+
+	for {
+		// 제어 이벤트를 먼저 비웁니다.
+		select {
+		case msg := <-c.controlQueue:
+			c.write(msg)
+			continue
+		default:
+		}
+
+		select {
+		case <-c.done:
+			c.drain()
+			return
+		case msg := <-c.controlQueue:
+			c.write(msg)
+		case msg := <-c.normalQueue:
+			c.write(msg)
+		}
+	}
+}
+
+// write 현재 연결로 메시지를 전송하고 결과를 알려줍니다.
+func (c *Client) write(msg outbound) {
+	c.connMu.Lock()
+	var err error
+	if c.conn == nil {
+		err = fmt.Errorf("connection is not established")
+	} else {
+		err = c.conn.WriteMessage(websocket.TextMessage, msg.message)
+	}
+	c.connMu.Unlock()
+
+	if err != nil {
+		log.Errorf("Error sending %s events: %v", msg.eventType, err)
+	} else {
+		c.metricsHook().EventSent(msg.eventType)
+	}
+	if msg.result != nil {
+		msg.result <- err
+	}
+}
+
+// drain 종료 시 남은 이벤트의 대기자에게 ErrClientClosed 를 알려줍니다.
+func (c *Client) drain() {
+	for {
+		select {
+		case msg := <-c.controlQueue:
+			if msg.result != nil {
+				msg.result <- ErrClientClosed
+			}
+		case msg := <-c.normalQueue:
+			if msg.result != nil {
+				msg.result <- ErrClientClosed
+			}
+		default:
+			return
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package persona
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"openai-realtime/pkg/openai"
+	"openai-realtime/pkg/openai/events"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultName 기본 페르소나
+const DefaultName = "tutor"
+
+// 바이너리에 포함된 기본 페르소나 (personas/<name>.json)
+//
+//go:embed personas/*.json
+var defaultPersonas embed.FS
+
+// Persona 프롬프트와 음성, 턴 감지, 도구, 첫 인사를 묶은 대화 상대 설정
+type Persona struct {
+	Name          string                `json:"name"`
+	Description   string                `json:"description,omitempty"`
+	Prompt        string                `json:"prompt"`             // text/template (Render 참고)
+	Defaults      Variables             `json:"defaults,omitempty"` // 템플릿 변수 기본값
+	Voice         string                `json:"voice,omitempty"`
+	Temperature   float64               `json:"temperature,omitempty"`    // 0 이면 세션 기본값
+	TurnDetection *events.TurnDetection `json:"turn_detection,omitempty"` // nil 이면 CLI 기본값
+	Tools         []string              `json:"tools,omitempty"`          // 노출할 도구 이름
+	Greeting      string                `json:"greeting,omitempty"`       // 세션 시작 시 어시스턴트가 먼저 건넬 인사 (text/template)
+}
+
+// Load name 페르소나를 읽어옵니다.
+// overrideDir 가 비어 있지 않고 그 안에 <name>.json 이 있으면 기본 페르소나 대신 사용합니다.
+func Load(name, overrideDir string) (*Persona, error) {
+	if name == "" {
+		name = DefaultName
+	}
+	if strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+		return nil, fmt.Errorf("invalid persona name %q", name)
+	}
+	fileName := name + ".json"
+
+	if overrideDir != "" {
+		data, err := os.ReadFile(filepath.Join(overrideDir, fileName))
+		if err == nil {
+			return decode(name, data)
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read persona %s: %w", name, err)
+		}
+	}
+
+	data, err := defaultPersonas.ReadFile("personas/" + fileName)
+	if err != nil {
+		names, _ := List(overrideDir)
+		return nil, fmt.Errorf("unknown persona %q (available: %s)", name, strings.Join(names, ", "))
+	}
+	return decode(name, data)
+}
+
+// List 사용할 수 있는 페르소나 이름 (기본 + overrideDir)
+func List(overrideDir string) ([]string, error) {
+	seen := make(map[string]bool)
+
+	entries, err := fs.ReadDir(defaultPersonas, "personas")
+	if err != nil {
+		return nil, err
+	}
+	if overrideDir != "" {
+		overrides, err := os.ReadDir(overrideDir)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read persona directory: %w", err)
+		}
+		entries = append(entries, overrides...)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok || entry.IsDir() || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func decode(name string, data []byte) (*Persona, error) {
+	var p Persona
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("invalid persona %s: %w", name, err)
+	}
+	if p.Name == "" {
+		p.Name = name
+	}
+	if strings.TrimSpace(p.Prompt) == "" {
+		return nil, fmt.Errorf("invalid persona %s: prompt is required", name)
+	}
+	return &p, nil
+}
+
+// SessionOptions 페르소나 설정을 세션 옵션으로 변환합니다.
+// 턴 감지는 페르소나에 설정된 경우에만 포함되므로 CLI 기본값 뒤에 적용해야 합니다.
+func (p *Persona) SessionOptions() []openai.SessionOption {
+	opts := []openai.SessionOption{openai.WithInstructions(p.Prompt)}
+	if p.Voice != "" {
+		opts = append(opts, openai.WithVoice(p.Voice))
+	}
+	if p.Temperature != 0 {
+		opts = append(opts, openai.WithTemperature(p.Temperature))
+	}
+	if p.TurnDetection != nil {
+		opts = append(opts, openai.WithTurnDetection(p.TurnDetection))
+	}
+	return opts
+}
+
+// Greet 첫 인사를 어시스턴트 응답으로 요청합니다. (Greeting 이 없으면 아무것도 하지 않음)
+func (p *Persona) Greet(c *openai.Client) error {
+	if p.Greeting == "" {
+		return nil
+	}
+	return c.ResponseCreate(&events.ResponseConfig{
+		Instructions: p.Prompt + "\n\nStart the conversation by greeting the user with exactly: " + p.Greeting,
+	})
+}
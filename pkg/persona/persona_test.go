@@ -0,0 +1,92 @@
+package persona
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"tutor.json":     `{"prompt": "overridden tutor"}`,
+		"custom.json":    `{"name": "Custom", "prompt": "custom prompt", "voice": "echo"}`,
+		"broken.json":    `{"prompt": `,
+		"noprompt.json":  `{"name": "noprompt", "prompt": "  "}`,
+		"notes.txt":      "not a persona",
+		"nested.json/ok": "",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name, persona, dir string
+		wantName, wantErr  string // wantErr 가 비어 있으면 성공
+		wantPrompt         string // 프롬프트 앞부분
+	}{
+		{name: "default", persona: "", wantName: DefaultName, wantPrompt: "Role:"},
+		{name: "embedded", persona: "doctor", wantName: "doctor", wantPrompt: "As an internal medicine specialist"},
+		{name: "override takes precedence", persona: "tutor", dir: dir, wantName: "tutor", wantPrompt: "overridden tutor"},
+		{name: "embedded when not overridden", persona: "friend", dir: dir, wantName: "friend", wantPrompt: "Act as a humorous"},
+		{name: "override only", persona: "custom", dir: dir, wantName: "Custom", wantPrompt: "custom prompt"},
+		{name: "missing override dir", persona: "tutor", dir: filepath.Join(dir, "missing"), wantName: "tutor", wantPrompt: "Role:"},
+		{name: "parent path", persona: "../tutor", dir: dir, wantErr: "invalid persona name"},
+		{name: "nested path", persona: "a/b", wantErr: "invalid persona name"},
+		{name: "windows path", persona: `a\b`, wantErr: "invalid persona name"},
+		{name: "dot dot", persona: "..", wantErr: "invalid persona name"},
+		{name: "unknown lists available", persona: "pirate", dir: dir, wantErr: `unknown persona "pirate" (available: broken, custom, doctor, friend, noprompt, tutor)`},
+		{name: "malformed json", persona: "broken", dir: dir, wantErr: "invalid persona broken"},
+		{name: "empty prompt", persona: "noprompt", dir: dir, wantErr: "prompt is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := Load(tt.persona, tt.dir)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Load(%q) = %v, want error containing %q", tt.persona, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load(%q): %v", tt.persona, err)
+			}
+			if p.Name != tt.wantName || !strings.HasPrefix(p.Prompt, tt.wantPrompt) {
+				t.Errorf("Load(%q) = %s %q, want %s %q...", tt.persona, p.Name, p.Prompt, tt.wantName, tt.wantPrompt)
+			}
+		})
+	}
+}
+
+func TestList(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"tutor.json", "custom.json", "readme.md"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	names, err := List(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"custom", "doctor", "friend", "tutor"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("List() = %v, want %v", names, want)
+	}
+
+	names, err = List("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"doctor", "friend", "tutor"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("List(\"\") = %v, want %v", names, want)
+	}
+}
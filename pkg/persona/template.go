@@ -0,0 +1,178 @@
+package persona
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+)
+
+// 세션 시작 시 자동으로 채워지는 변수
+const (
+	VarDate    = "date"    // 오늘 날짜 (2006-01-02)
+	VarTime    = "time"    // 현재 시각 (15:04)
+	VarSummary = "summary" // 이전 세션 요약 (없으면 빈 문자열)
+)
+
+// Variables 프롬프트와 인사말 템플릿에 넘길 변수 ({{.name}} 형태로 참조)
+type Variables map[string]string
+
+// MissingVariablesError 템플릿이 참조하지만 값이 없는 변수가 있을 때 반환됩니다.
+type MissingVariablesError struct {
+	Persona string
+	Names   []string
+}
+
+func (e *MissingVariablesError) Error() string {
+	return fmt.Sprintf("persona %s: missing template variables: %s (set with --var name=value)", e.Persona, strings.Join(e.Names, ", "))
+}
+
+// Render 프롬프트와 인사말을 text/template 으로 렌더링한 복사본을 반환합니다.
+// 변수 값은 자동 변수(date, time, summary), 페르소나의 defaults, vars 순으로 덮어씁니다.
+// 참조하는 변수 중 값이 없는 것이 있으면 모두 모아 *MissingVariablesError 로 반환합니다.
+// 단, {{if .name}} / {{with .name}} 의 조건과 그 조건 안에서만 쓰는 변수는 선택이며, 값이 없으면 빈 문자열로 채워집니다.
+func (p *Persona) Render(vars Variables, now time.Time) (*Persona, error) {
+	data := map[string]string{
+		VarDate:    now.Format("2006-01-02"),
+		VarTime:    now.Format("15:04"),
+		VarSummary: "",
+	}
+	for name, value := range p.Defaults {
+		data[name] = value
+	}
+	for name, value := range vars {
+		data[name] = value
+	}
+
+	prompt, err := parseTemplate(p.Name+" prompt", p.Prompt)
+	if err != nil {
+		return nil, err
+	}
+	greeting, err := parseTemplate(p.Name+" greeting", p.Greeting)
+	if err != nil {
+		return nil, err
+	}
+
+	missing := make(map[string]bool)
+	var optional []string
+	for _, t := range []*template.Template{prompt, greeting} {
+		required, guarded := referencedVariables(t)
+		for _, name := range required {
+			if _, ok := data[name]; !ok {
+				missing[name] = true
+			}
+		}
+		optional = append(optional, guarded...)
+	}
+	if len(missing) > 0 {
+		names := make([]string, 0, len(missing))
+		for name := range missing {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, &MissingVariablesError{Persona: p.Name, Names: names}
+	}
+	for _, name := range optional {
+		if _, ok := data[name]; !ok {
+			data[name] = "" // missingkey=error 이므로 조건이 거짓이 되도록 채움
+		}
+	}
+
+	rendered := *p
+	if rendered.Prompt, err = execute(prompt, data); err != nil {
+		return nil, err
+	}
+	if rendered.Greeting, err = execute(greeting, data); err != nil {
+		return nil, err
+	}
+	return &rendered, nil
+}
+
+func parseTemplate(name, text string) (*template.Template, error) {
+	t, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("persona %w", err)
+	}
+	return t, nil
+}
+
+func execute(t *template.Template, data map[string]string) (string, error) {
+	var sb strings.Builder
+	if err := t.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("persona %w", err)
+	}
+	return sb.String(), nil
+}
+
+// referencedVariables 템플릿이 참조하는 최상위 변수 이름 ({{.name}})
+// if/with 의 조건에 쓰인 변수와, 같은 변수를 검사하는 if 본문 안에서 쓰인 변수는 optional 로 분류합니다.
+func referencedVariables(t *template.Template) (required, optional []string) {
+	record := func(names []string, guarded map[string]bool) {
+		for _, name := range names {
+			if guarded[name] {
+				optional = append(optional, name)
+			} else {
+				required = append(required, name)
+			}
+		}
+	}
+
+	var walk func(node parse.Node, guarded map[string]bool)
+	walk = func(node parse.Node, guarded map[string]bool) {
+		switch n := node.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return
+			}
+			for _, child := range n.Nodes {
+				walk(child, guarded)
+			}
+		case *parse.ActionNode:
+			record(pipeVariables(n.Pipe), guarded)
+		case *parse.IfNode:
+			condition := pipeVariables(n.Pipe)
+			optional = append(optional, condition...)
+			inner := make(map[string]bool, len(guarded)+len(condition))
+			for name := range guarded {
+				inner[name] = true
+			}
+			for _, name := range condition {
+				inner[name] = true
+			}
+			walk(n.List, inner)
+			walk(n.ElseList, guarded)
+		case *parse.RangeNode:
+			record(pipeVariables(n.Pipe), guarded) // 본문에서는 . 이 바뀌므로 검사하지 않음
+			walk(n.ElseList, guarded)
+		case *parse.WithNode:
+			optional = append(optional, pipeVariables(n.Pipe)...) // 본문에서는 . 이 바뀜
+			walk(n.ElseList, guarded)
+		}
+	}
+
+	if t.Tree != nil {
+		walk(t.Tree.Root, nil)
+	}
+	return required, optional
+}
+
+// pipeVariables 파이프라인이 참조하는 최상위 변수 이름
+func pipeVariables(pipe *parse.PipeNode) []string {
+	if pipe == nil {
+		return nil
+	}
+	var names []string
+	for _, cmd := range pipe.Cmds {
+		for _, arg := range cmd.Args {
+			switch a := arg.(type) {
+			case *parse.FieldNode:
+				names = append(names, a.Ident[0])
+			case *parse.PipeNode:
+				names = append(names, pipeVariables(a)...)
+			}
+		}
+	}
+	return names
+}
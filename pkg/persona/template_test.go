@@ -0,0 +1,134 @@
+package persona
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+)
+
+var testNow = time.Date(2024, 10, 1, 9, 30, 0, 0, time.UTC)
+
+func TestReferencedVariables(t *testing.T) {
+	tests := []struct {
+		name, text         string
+		required, optional []string
+	}{
+		{"plain", "{{.a}} and {{.b}}", []string{"a", "b"}, nil},
+		{"pipeline", `{{printf "%s-%s" .a (print .b)}}`, []string{"a", "b"}, nil},
+		{"if guards its own variable", "{{if .a}}{{.a}} {{.b}}{{end}}", []string{"b"}, []string{"a", "a"}},
+		{"else is not guarded", "{{if .a}}yes{{else}}{{.a}}{{end}}", []string{"a"}, []string{"a"}},
+		{"nested if", "{{if .a}}{{if .b}}{{.a}}{{.b}}{{end}}{{end}}", nil, []string{"a", "b", "a", "b"}},
+		{"with changes dot", "{{with .a}}{{.unrelated}}{{else}}{{.b}}{{end}}", []string{"b"}, []string{"a"}},
+		{"range pipe is required", "{{range .a}}{{.x}}{{else}}{{.b}}{{end}}", []string{"a", "b"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := template.Must(template.New(tt.name).Parse(tt.text))
+			required, optional := referencedVariables(tmpl)
+			sort.Strings(required)
+			sort.Strings(optional)
+			sort.Strings(tt.optional)
+			if !reflect.DeepEqual(required, tt.required) || !reflect.DeepEqual(optional, tt.optional) {
+				t.Errorf("referencedVariables() = %v, %v, want %v, %v", required, optional, tt.required, tt.optional)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	p := &Persona{
+		Name:     "test",
+		Prompt:   "I am {{.assistant_name}}.{{if .user_name}} Hello {{.user_name}}.{{end}} Today is {{.date}}.{{if .summary}} {{.summary}}{{end}}",
+		Defaults: Variables{"assistant_name": "Potato"},
+		Greeting: "안녕{{with .user_name}} {{.}}{{end}}!",
+	}
+
+	rendered, err := p.Render(nil, testNow)
+	if err != nil {
+		t.Fatalf("Render() without variables: %v", err)
+	}
+	if want := "I am Potato. Today is 2024-10-01."; rendered.Prompt != want {
+		t.Errorf("prompt = %q, want %q", rendered.Prompt, want)
+	}
+	if want := "안녕!"; rendered.Greeting != want {
+		t.Errorf("greeting = %q, want %q", rendered.Greeting, want)
+	}
+
+	rendered, err = p.Render(Variables{"assistant_name": "Tomato", "user_name": "민준", VarSummary: "Likes dinosaurs."}, testNow)
+	if err != nil {
+		t.Fatalf("Render(): %v", err)
+	}
+	if want := "I am Tomato. Hello 민준. Today is 2024-10-01. Likes dinosaurs."; rendered.Prompt != want {
+		t.Errorf("prompt = %q, want %q", rendered.Prompt, want)
+	}
+	if want := "안녕 민준!"; rendered.Greeting != want {
+		t.Errorf("greeting = %q, want %q", rendered.Greeting, want)
+	}
+	if p.Prompt == rendered.Prompt {
+		t.Error("Render() modified the original persona")
+	}
+}
+
+func TestRenderMissingVariables(t *testing.T) {
+	p := &Persona{
+		Name:     "test",
+		Prompt:   "{{.language}} {{if .user_name}}{{.age}}{{end}}",
+		Greeting: "{{.nickname}} {{.language}}",
+	}
+
+	_, err := p.Render(nil, testNow)
+	var missing *MissingVariablesError
+	if !errors.As(err, &missing) {
+		t.Fatalf("Render() = %v, want *MissingVariablesError", err)
+	}
+	if want := []string{"age", "language", "nickname"}; !reflect.DeepEqual(missing.Names, want) {
+		t.Errorf("missing = %v, want %v", missing.Names, want)
+	}
+	if !strings.Contains(err.Error(), "--var") {
+		t.Errorf("error %q does not mention --var", err)
+	}
+
+	if _, err := p.Render(Variables{"language": "English", "nickname": "Min", "age": "9"}, testNow); err != nil {
+		t.Errorf("Render() with all variables: %v", err)
+	}
+}
+
+func TestRenderBuiltInPersonasWithoutVariables(t *testing.T) {
+	names, err := List("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range names {
+		p, err := Load(name, "")
+		if err != nil {
+			t.Fatalf("Load(%s): %v", name, err)
+		}
+		rendered, err := p.Render(nil, testNow)
+		if err != nil {
+			t.Errorf("Render(%s) without --var: %v", name, err)
+			continue
+		}
+		if strings.Contains(rendered.Prompt, "{{") || strings.Contains(rendered.Greeting, "{{") {
+			t.Errorf("persona %s was not fully rendered", name)
+		}
+	}
+
+	tutor, err := Load(DefaultName, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rendered, err := tutor.Render(Variables{"user_name": "민준", "age": "9"}, testNow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(rendered.Prompt, "You are talking with 민준, who is 9 years old.\n") {
+		t.Errorf("tutor prompt does not introduce the user:\n%s", rendered.Prompt)
+	}
+	if want := "안녕 민준! 오늘은 어떤 재미있는 얘기를 해볼까?"; rendered.Greeting != want {
+		t.Errorf("tutor greeting = %q, want %q", rendered.Greeting, want)
+	}
+}
@@ -0,0 +1,134 @@
+package tools
+
+import (
+	"context"
+	"openai-realtime/pkg/openai"
+	"openai-realtime/pkg/openai/events"
+	"sync"
+)
+
+// pendingResponse 하나의 응답에서 요청된 함수 호출의 진행 상황
+type pendingResponse struct {
+	calls     int  // 실행 중인 함수 호출 수
+	done      bool // response.done 수신 여부
+	completed bool // 응답이 정상 완료되었는지 여부 (취소/실패 시 후속 응답을 요청하지 않음)
+}
+
+// executor 모델의 함수 호출을 실행하고 결과를 돌려보냅니다.
+type executor struct {
+	registry *Registry
+	client   *openai.Client
+
+	mu        sync.Mutex
+	names     map[string]string // call_id -> 함수 이름
+	responses map[string]*pendingResponse
+}
+
+// Attach 클라이언트에 도구 실행기를 연결합니다.
+// response.function_call_arguments.done 을 받으면 도구를 실행해 function_call_output 을 전송하고,
+// 응답의 모든 함수 호출이 끝나면 후속 response.create 를 요청합니다.
+// 반환된 함수를 호출하면 연결이 해제됩니다.
+func (r *Registry) Attach(c *openai.Client) (detach func()) {
+	e := &executor{
+		registry:  r,
+		client:    c,
+		names:     make(map[string]string),
+		responses: make(map[string]*pendingResponse),
+	}
+
+	unsubscribes := []func(){
+		openai.On(c, events.ResponseOutputItemAddedEventType, e.onOutputItemAdded),
+		openai.On(c, events.ResponseFunctionCallArgumentsDoneEventType, e.onArgumentsDone),
+		openai.On(c, events.ResponseDoneEventType, e.onResponseDone),
+	}
+
+	return func() {
+		for _, unsubscribe := range unsubscribes {
+			unsubscribe()
+		}
+	}
+}
+
+func (e *executor) onOutputItemAdded(ctx context.Context, event events.ResponseOutputItemAdded) {
+	if event.Item.Type != events.ItemTypeFunctionCall {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.names[event.Item.CallID] = event.Item.Name
+}
+
+func (e *executor) onArgumentsDone(ctx context.Context, event events.ResponseFunctionCallArgumentsDone) {
+	e.mu.Lock()
+	name := event.Name
+	if name == "" {
+		name = e.names[event.CallID]
+	}
+	delete(e.names, event.CallID)
+
+	pending := e.pending(event.ResponseID)
+	pending.calls++
+	e.mu.Unlock()
+
+	// 수신 goroutine 을 막지 않도록 별도 goroutine 에서 실행합니다.
+	go func() {
+		log.Infof("Calling tool %s (call_id: %s)", name, event.CallID)
+		output := e.registry.Call(ctx, name, event.Arguments)
+
+		if err := e.client.FunctionCallOutput(event.CallID, output); err != nil {
+			log.Errorf("Failed to send function call output for %s: %v", event.CallID, err)
+		}
+
+		e.mu.Lock()
+		pending.calls--
+		ready := pending.done && pending.calls == 0
+		if ready {
+			delete(e.responses, event.ResponseID)
+		}
+		e.mu.Unlock()
+
+		if ready && pending.completed {
+			e.requestFollowUp()
+		}
+	}()
+}
+
+func (e *executor) onResponseDone(ctx context.Context, event events.ResponseDone) {
+	e.mu.Lock()
+	pending, ok := e.responses[event.Response.ID]
+	if !ok {
+		e.mu.Unlock()
+		return
+	}
+
+	pending.done = true
+	pending.completed = event.Response.Status == events.ResponseStatusCompleted
+	ready := pending.calls == 0
+	if ready {
+		delete(e.responses, event.Response.ID)
+	}
+	e.mu.Unlock()
+
+	if ready && pending.completed {
+		// 한도 때문에 기다릴 수 있으므로 수신 goroutine 을 막지 않습니다.
+		go e.requestFollowUp()
+	}
+}
+
+// pending 응답별 진행 상황을 반환합니다. (e.mu 를 잡은 상태에서 호출)
+func (e *executor) pending(responseID string) *pendingResponse {
+	pending, ok := e.responses[responseID]
+	if !ok {
+		pending = &pendingResponse{}
+		e.responses[responseID] = pending
+	}
+	return pending
+}
+
+// requestFollowUp 함수 호출 결과를 바탕으로 모델이 답변하도록 응답을 요청합니다.
+func (e *executor) requestFollowUp() {
+	if err := e.client.ResponseCreate(nil); err != nil {
+		log.Errorf("Failed to request follow-up response: %v", err)
+	}
+}
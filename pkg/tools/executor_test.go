@@ -0,0 +1,226 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"openai-realtime/pkg/openai"
+	"openai-realtime/pkg/openai/events"
+	"openai-realtime/pkg/openai/openaitest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const testTimeout = 2 * time.Second
+
+type addArgs struct {
+	A int `json:"a"`
+	B int `json:"b"`
+}
+
+// newTestClient 모의 서버에 연결하고 수신 루프를 시작한 클라이언트
+func newTestClient(t *testing.T) (*openai.Client, *openaitest.Server) {
+	t.Helper()
+
+	srv := openaitest.NewServer()
+	ctx, cancel := context.WithCancel(context.Background())
+	c, err := openai.NewClient(ctx, srv.URL(), "/v1/realtime", "gpt-4o-realtime-preview-2024-10-01", "test-key")
+	if err != nil {
+		cancel()
+		srv.Close()
+		t.Fatalf("NewClient: %v", err)
+	}
+	go c.ReceiveServerEvent(ctx, cancel)
+
+	t.Cleanup(func() {
+		_ = c.Close()
+		cancel()
+		srv.Close()
+	})
+	return c, srv
+}
+
+// functionCallOutputs 서버가 받은 function_call_output 아이템
+func functionCallOutputs(t *testing.T, srv *openaitest.Server, count int) []events.Item {
+	t.Helper()
+	received, err := srv.WaitFor(openai.ConversationItemCreateEventType, count, testTimeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var items []events.Item
+	for _, e := range received {
+		var created struct {
+			Item events.Item `json:"item"`
+		}
+		if err := json.Unmarshal(e.Raw, &created); err != nil {
+			t.Fatal(err)
+		}
+		if created.Item.Type == events.ItemTypeFunctionCallOutput {
+			items = append(items, created.Item)
+		}
+	}
+	return items
+}
+
+// countReceived 서버가 받은 eventType 이벤트 수
+func countReceived(srv *openaitest.Server, eventType string) int {
+	n := 0
+	for _, e := range srv.Received() {
+		if e.Type == eventType {
+			n++
+		}
+	}
+	return n
+}
+
+func TestExecutorCallsToolAndRequestsFollowUp(t *testing.T) {
+	c, srv := newTestClient(t)
+
+	registry := NewRegistry(time.Second)
+	var calls atomic.Int32
+	if err := Register(registry, "add", "adds two numbers", func(ctx context.Context, args addArgs) (interface{}, error) {
+		calls.Add(1)
+		return args.A + args.B, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	registry.Attach(c)
+
+	followUp := make(chan events.ResponseDone, 1)
+	openai.On(c, events.ResponseDoneEventType, func(ctx context.Context, e events.ResponseDone) {
+		if len(e.Response.Output) > 0 && e.Response.Output[0].Type == events.ItemTypeMessage {
+			followUp <- e
+		}
+	})
+
+	srv.Script(
+		openaitest.Response{FunctionCall: &openaitest.FunctionCall{Name: "add", CallID: "call_1", Arguments: `{"a": 1, "b": 2}`}},
+		openaitest.Response{Text: "1 + 2 = 3"},
+	)
+	if err := c.ResponseCreate(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	outputs := functionCallOutputs(t, srv, 1)
+	if len(outputs) != 1 || outputs[0].CallID != "call_1" || outputs[0].Output != "3" {
+		t.Fatalf("function_call_output = %+v, want call_1 with 3", outputs)
+	}
+
+	select {
+	case <-followUp:
+	case <-time.After(testTimeout):
+		t.Fatal("timed out waiting for the follow-up response")
+	}
+	time.Sleep(50 * time.Millisecond)
+	if got := countReceived(srv, openai.ResponseCreateEventType); got != 2 {
+		t.Errorf("sent %d response.create, want the initial one and exactly one follow-up", got)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("tool called %d times, want 1", got)
+	}
+}
+
+func TestExecutorSkipsFollowUpForUnfinishedResponses(t *testing.T) {
+	for _, status := range []string{events.ResponseStatusCancelled, events.ResponseStatusFailed} {
+		t.Run(status, func(t *testing.T) {
+			c, srv := newTestClient(t)
+
+			registry := NewRegistry(time.Second)
+			if err := Register(registry, "add", "adds two numbers", func(ctx context.Context, args addArgs) (interface{}, error) {
+				return args.A + args.B, nil
+			}); err != nil {
+				t.Fatal(err)
+			}
+			registry.Attach(c)
+
+			srv.Script(openaitest.Response{
+				FunctionCall: &openaitest.FunctionCall{Name: "add", CallID: "call_1", Arguments: `{"a": 1, "b": 2}`},
+				Status:       status,
+			})
+			if err := c.ResponseCreate(nil); err != nil {
+				t.Fatal(err)
+			}
+
+			functionCallOutputs(t, srv, 1)
+			time.Sleep(100 * time.Millisecond)
+			if got := countReceived(srv, openai.ResponseCreateEventType); got != 1 {
+				t.Errorf("sent %d response.create, want no follow-up after a %s response", got, status)
+			}
+		})
+	}
+}
+
+func TestExecutorReportsToolErrorsToModel(t *testing.T) {
+	c, srv := newTestClient(t)
+
+	registry := NewRegistry(50 * time.Millisecond)
+	if err := Register(registry, "explode", "panics", func(ctx context.Context, args struct{}) (interface{}, error) {
+		panic("boom")
+	}); err != nil {
+		t.Fatal(err)
+	}
+	registry.Attach(c)
+
+	srv.Script(openaitest.Response{FunctionCall: &openaitest.FunctionCall{Name: "explode", CallID: "call_1", Arguments: `{}`}})
+	if err := c.ResponseCreate(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	outputs := functionCallOutputs(t, srv, 1)
+	if len(outputs) != 1 || !strings.Contains(outputs[0].Output, "tool panicked: boom") {
+		t.Fatalf("function_call_output = %+v, want the panic reported", outputs)
+	}
+	if _, err := srv.WaitFor(openai.ResponseCreateEventType, 2, testTimeout); err != nil {
+		t.Errorf("no follow-up response after a failed tool: %v", err)
+	}
+	if err := c.Err(); err != nil {
+		t.Fatalf("session ended: %v", err)
+	}
+}
+
+func TestRegistryCall(t *testing.T) {
+	registry := NewRegistry(50 * time.Millisecond)
+	mustRegister := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	mustRegister(Register(registry, "add", "", func(ctx context.Context, args addArgs) (interface{}, error) {
+		return map[string]int{"sum": args.A + args.B}, nil
+	}))
+	mustRegister(Register(registry, "slow", "", func(ctx context.Context, args struct{}) (interface{}, error) {
+		<-ctx.Done()
+		time.Sleep(time.Second) // 컨텍스트를 무시하고 늦게 끝나는 도구
+		return "late", nil
+	}))
+	mustRegister(Register(registry, "explode", "", func(ctx context.Context, args struct{}) (interface{}, error) {
+		panic("boom")
+	}))
+
+	tests := []struct {
+		name, tool, arguments, want string
+	}{
+		{"result", "add", `{"a": 2, "b": 3}`, `{"sum":5}`},
+		{"invalid arguments", "add", `{"a": "two"}`, `{"error":"invalid arguments: arguments: missing required property \"b\"; a: expected integer, got string"}`},
+		{"unknown tool", "missing", `{}`, `{"error":"unknown tool: missing"}`},
+		{"timeout", "slow", `{}`, `{"error":"tool slow timed out: context deadline exceeded"}`},
+		{"panic", "explode", `{}`, `{"error":"tool panicked: boom"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			if got := registry.Call(context.Background(), tt.tool, tt.arguments); got != tt.want {
+				t.Errorf("Call(%s) = %s, want %s", tt.tool, got, tt.want)
+			}
+			if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+				t.Errorf("Call(%s) took %s, want it bounded by the timeout", tt.tool, elapsed)
+			}
+		})
+	}
+
+	if err := Register(registry, "add", "", func(ctx context.Context, args addArgs) (interface{}, error) { return nil, nil }); err == nil {
+		t.Error("registering a duplicate tool succeeded")
+	}
+}
@@ -0,0 +1,178 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"openai-realtime/pkg/config"
+	"openai-realtime/pkg/openai/events"
+	"os"
+	"sync"
+	"time"
+)
+
+const defaultTimeout = 10 * time.Second
+
+var log = func() *logrus.Logger {
+	log := logrus.New()
+	log.SetOutput(os.Stdout)
+	log.SetLevel(config.LogLevel)
+	return log
+}()
+
+// tool 등록된 함수와 Realtime API 에 노출할 정의
+type tool struct {
+	name        string
+	description string
+	schema      *Schema
+	invoke      func(ctx context.Context, arguments string) (interface{}, error)
+}
+
+// Registry 모델이 호출할 수 있는 Go 함수 목록
+type Registry struct {
+	mu    sync.RWMutex
+	tools map[string]*tool
+	order []string
+
+	timeout time.Duration
+}
+
+// NewRegistry 도구 레지스트리 생성자 함수. timeout 은 도구 하나의 최대 실행 시간입니다. (0 이면 기본값)
+func NewRegistry(timeout time.Duration) *Registry {
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return &Registry{
+		tools:   make(map[string]*tool),
+		timeout: timeout,
+	}
+}
+
+// Register 인자 구조체 T 를 받는 Go 함수를 도구로 등록합니다.
+// T 의 필드와 태그로부터 parameters 스키마가 생성되며 (GenerateSchema 참고),
+// 모델이 보낸 인자는 같은 스키마로 검증된 뒤 T 로 디코딩되어 handler 에 전달됩니다.
+// 검증에 실패하면 handler 는 호출되지 않고 오류 내용이 모델에게 전달됩니다.
+// handler 의 반환값은 JSON 으로 인코딩되어 모델에게 전달됩니다.
+func Register[T any](r *Registry, name, description string, handler func(ctx context.Context, args T) (interface{}, error)) error {
+	if name == "" {
+		return fmt.Errorf("tool name is required")
+	}
+
+	var zero T
+	schema, err := GenerateSchema(zero)
+	if err != nil {
+		return fmt.Errorf("tool %s: %w", name, err)
+	}
+
+	t := &tool{
+		name:        name,
+		description: description,
+		schema:      schema,
+		invoke: func(ctx context.Context, arguments string) (interface{}, error) {
+			var args T
+			if err := schema.Decode(arguments, &args); err != nil {
+				return nil, err
+			}
+			return handler(ctx, args)
+		},
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.tools[name]; exists {
+		return fmt.Errorf("tool %s is already registered", name)
+	}
+	r.tools[name] = t
+	r.order = append(r.order, name)
+	return nil
+}
+
+// Tools session.update 에 전달할 도구 정의 목록을 등록 순서대로 반환합니다.
+func (r *Registry) Tools() []events.Tool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list := make([]events.Tool, 0, len(r.order))
+	for _, name := range r.order {
+		t := r.tools[name]
+		list = append(list, events.Tool{
+			Type:        "function",
+			Name:        t.name,
+			Description: t.description,
+			Parameters:  t.schema.Map(),
+		})
+	}
+	return list
+}
+
+// ToolsNamed names 에 해당하는 도구 정의만 names 순서대로 반환합니다. 등록되지 않은 이름이 있으면 오류를 반환합니다.
+func (r *Registry) ToolsNamed(names []string) ([]events.Tool, error) {
+	all := r.Tools()
+	byName := make(map[string]events.Tool, len(all))
+	for _, t := range all {
+		byName[t.Name] = t
+	}
+
+	list := make([]events.Tool, 0, len(names))
+	for _, name := range names {
+		t, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown tool: %s", name)
+		}
+		list = append(list, t)
+	}
+	return list, nil
+}
+
+// Call 이름으로 도구를 실행하고 모델에게 돌려줄 output 문자열을 반환합니다.
+// 실행 오류는 {"error": "..."} 형태의 output 으로 변환되어 모델이 이를 보고 대응할 수 있습니다.
+func (r *Registry) Call(ctx context.Context, name, arguments string) string {
+	r.mu.RLock()
+	t, ok := r.tools[name]
+	r.mu.RUnlock()
+
+	if !ok {
+		return errorOutput(fmt.Errorf("unknown tool: %s", name))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	type result struct {
+		value interface{}
+		err   error
+	}
+	resultChan := make(chan result, 1)
+
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				resultChan <- result{err: fmt.Errorf("tool panicked: %v", p)}
+			}
+		}()
+		value, err := t.invoke(ctx, arguments)
+		resultChan <- result{value: value, err: err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return errorOutput(fmt.Errorf("tool %s timed out: %w", name, ctx.Err()))
+	case res := <-resultChan:
+		if res.err != nil {
+			return errorOutput(res.err)
+		}
+
+		output, err := json.Marshal(res.value)
+		if err != nil {
+			return errorOutput(fmt.Errorf("failed to encode result: %w", err))
+		}
+		return string(output)
+	}
+}
+
+func errorOutput(err error) string {
+	output, _ := json.Marshal(map[string]string{"error": err.Error()})
+	return string(output)
+}
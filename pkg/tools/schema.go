@@ -0,0 +1,373 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Schema Realtime API 도구 parameters 가 허용하는 JSON Schema 부분집합
+type Schema struct {
+	Type                 string             `json:"type"`
+	Description          string             `json:"description,omitempty"`
+	Format               string             `json:"format,omitempty"`          // time.Time: date-time
+	ContentEncoding      string             `json:"contentEncoding,omitempty"` // []byte: base64
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"` // map[string]T 의 값
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+
+	order []string // 속성 선언 순서 (검증 오류를 일정한 순서로 보고하기 위함)
+}
+
+// GenerateSchema 구조체의 필드와 태그로부터 JSON Schema 를 생성합니다.
+//
+// 지원하는 태그:
+//   - json:"name,omitempty"  속성 이름. omitempty 가 없으면 required
+//   - required:"true|false"  required 여부를 명시적으로 지정
+//   - description:"..."      속성 설명
+//   - enum:"a,b,c"           허용 값 목록 (문자열, 정수, 실수)
+//   - minimum:"0" maximum:"10"           숫자 범위
+//   - minLength:"1" maxLength:"20"       문자열 길이
+//   - minItems:"1" maxItems:"5"          배열 길이
+//
+// 필드는 encoding/json 과 같은 규칙으로 읽습니다. 이름 없는 임베드 구조체의 필드는 바깥 구조체의 속성이 되며,
+// map[string]T 는 값이 T 인 object (additionalProperties), time.Time 은 RFC 3339 문자열 (format: date-time),
+// []byte 는 base64 문자열 (contentEncoding: base64) 입니다.
+func GenerateSchema(v interface{}) (*Schema, error) {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return nil, fmt.Errorf("arguments type must be a struct")
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("arguments type must be a struct, got %s", t.Kind())
+	}
+	return typeSchema(t, nil)
+}
+
+// Map events.Tool.Parameters 에 넣을 수 있는 map 형태로 변환합니다.
+func (s *Schema) Map() map[string]interface{} {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil
+	}
+	return m
+}
+
+// typeSchema visiting 은 재귀 구조체를 감지하기 위한 방문 중인 타입 목록입니다.
+func typeSchema(t reflect.Type, visiting []reflect.Type) (*Schema, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}, nil
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		return &Schema{Type: "string", ContentEncoding: "base64"}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}, nil
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}, nil
+	case reflect.Slice, reflect.Array:
+		items, err := typeSchema(t.Elem(), visiting)
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "array", Items: items}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("map key type %s is not supported (must be string)", t.Key())
+		}
+		values, err := typeSchema(t.Elem(), visiting)
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "object", AdditionalProperties: values}, nil
+	case reflect.Struct:
+		for _, v := range visiting {
+			if v == t {
+				return nil, fmt.Errorf("recursive type %s is not supported", t)
+			}
+		}
+		visiting = append(visiting, t)
+
+		schema := &Schema{
+			Type:       "object",
+			Properties: make(map[string]*Schema),
+			Required:   make([]string, 0),
+		}
+		fields, err := jsonFields(t)
+		if err != nil {
+			return nil, err
+		}
+		for _, field := range fields {
+			property, err := typeSchema(field.Type, visiting)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", field.Name, err)
+			}
+			if err := applyTags(property, field.Type, field.Tag); err != nil {
+				return nil, fmt.Errorf("field %s: %w", field.Name, err)
+			}
+
+			required := !field.omitempty
+			if tag, ok := field.Tag.Lookup("required"); ok {
+				if required, err = strconv.ParseBool(tag); err != nil {
+					return nil, fmt.Errorf("field %s: invalid required tag %q", field.Name, tag)
+				}
+			}
+
+			schema.Properties[field.name] = property
+			schema.order = append(schema.order, field.name)
+			if required {
+				schema.Required = append(schema.Required, field.name)
+			}
+		}
+		return schema, nil
+	default:
+		return nil, fmt.Errorf("unsupported type %s", t)
+	}
+}
+
+// applyTags description, enum, 범위/길이 태그를 스키마에 반영합니다.
+func applyTags(schema *Schema, t reflect.Type, tag reflect.StructTag) error {
+	schema.Description = tag.Get("description")
+
+	if enum := tag.Get("enum"); enum != "" {
+		for _, raw := range strings.Split(enum, ",") {
+			value, err := parseEnumValue(schema.Type, strings.TrimSpace(raw))
+			if err != nil {
+				return err
+			}
+			schema.Enum = append(schema.Enum, value)
+		}
+	}
+
+	floats := map[string]**float64{"minimum": &schema.Minimum, "maximum": &schema.Maximum}
+	for name, target := range floats {
+		if raw, ok := tag.Lookup(name); ok {
+			if schema.Type != "integer" && schema.Type != "number" {
+				return fmt.Errorf("%s tag is only allowed on numeric fields, not %s", name, t)
+			}
+			value, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				return fmt.Errorf("invalid %s tag %q", name, raw)
+			}
+			*target = &value
+		}
+	}
+
+	ints := map[string]struct {
+		target **int
+		kind   string
+	}{
+		"minLength": {&schema.MinLength, "string"},
+		"maxLength": {&schema.MaxLength, "string"},
+		"minItems":  {&schema.MinItems, "array"},
+		"maxItems":  {&schema.MaxItems, "array"},
+	}
+	for name, spec := range ints {
+		if raw, ok := tag.Lookup(name); ok {
+			if schema.Type != spec.kind {
+				return fmt.Errorf("%s tag is only allowed on %s fields, not %s", name, spec.kind, t)
+			}
+			value, err := strconv.Atoi(raw)
+			if err != nil || value < 0 {
+				return fmt.Errorf("invalid %s tag %q", name, raw)
+			}
+			*spec.target = &value
+		}
+	}
+	return nil
+}
+
+func parseEnumValue(schemaType, raw string) (interface{}, error) {
+	switch schemaType {
+	case "string":
+		return raw, nil
+	case "integer":
+		value, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer enum value %q", raw)
+		}
+		return value, nil
+	case "number":
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number enum value %q", raw)
+		}
+		return value, nil
+	default:
+		return nil, fmt.Errorf("enum tag is not supported on %s fields", schemaType)
+	}
+}
+
+// jsonField encoding/json 이 읽는 필드 (임베드된 구조체에서 올라온 필드 포함)
+type jsonField struct {
+	reflect.StructField
+	name      string
+	omitempty bool
+	tagged    bool  // json 태그로 이름을 지정했는지 여부
+	index     []int // 바깥 구조체부터의 필드 위치
+}
+
+// jsonFields encoding/json 과 같은 규칙으로 t 의 필드를 선언 순서대로 모읍니다.
+// 이름이 겹치면 가장 얕은 필드가, 깊이가 같으면 json 태그로 이름을 지정한 필드가 남고, 그래도 겹치면 모두 버립니다.
+func jsonFields(t reflect.Type) ([]jsonField, error) {
+	var candidates []jsonField
+	if err := collectJSONFields(t, nil, map[reflect.Type]bool{t: true}, &candidates); err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string][]jsonField)
+	for _, field := range candidates {
+		byName[field.name] = append(byName[field.name], field)
+	}
+
+	fields := make([]jsonField, 0, len(byName))
+	for _, named := range byName {
+		if field, ok := dominantField(named); ok {
+			fields = append(fields, field)
+		}
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		a, b := fields[i].index, fields[j].index
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+	return fields, nil
+}
+
+// collectJSONFields t 의 필드를 모으고, 이름 없는 임베드 구조체는 그 필드를 대신 모읍니다.
+// embedding 은 재귀 임베드를 막기 위한 임베드 중인 타입 목록입니다.
+func collectJSONFields(t reflect.Type, index []int, embedding map[reflect.Type]bool, fields *[]jsonField) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, omitempty, skip := jsonFieldName(field)
+		if skip {
+			continue
+		}
+		tagged := strings.Split(field.Tag.Get("json"), ",")[0] != ""
+		fieldIndex := append(append([]int(nil), index...), i)
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous {
+			if !field.IsExported() && fieldType.Kind() != reflect.Struct {
+				continue
+			}
+			if !tagged && fieldType.Kind() == reflect.Struct {
+				if !field.IsExported() && field.Type.Kind() == reflect.Ptr {
+					// encoding/json 이 nil 포인터를 만들 수 없어 디코딩에 실패함
+					return fmt.Errorf("field %s: embedded pointer to unexported type %s is not supported", field.Name, fieldType)
+				}
+				if !embedding[fieldType] {
+					embedding[fieldType] = true
+					err := collectJSONFields(fieldType, fieldIndex, embedding, fields)
+					delete(embedding, fieldType)
+					if err != nil {
+						return err
+					}
+				}
+				continue
+			}
+		} else if !field.IsExported() {
+			continue
+		}
+
+		*fields = append(*fields, jsonField{
+			StructField: field,
+			name:        name,
+			omitempty:   omitempty,
+			tagged:      tagged,
+			index:       fieldIndex,
+		})
+	}
+	return nil
+}
+
+// dominantField 이름이 같은 필드 중 encoding/json 이 사용하는 필드
+func dominantField(fields []jsonField) (jsonField, bool) {
+	depth := len(fields[0].index)
+	for _, field := range fields {
+		if len(field.index) < depth {
+			depth = len(field.index)
+		}
+	}
+
+	var shallowest []jsonField
+	tagged := 0
+	for _, field := range fields {
+		if len(field.index) == depth {
+			shallowest = append(shallowest, field)
+			if field.tagged {
+				tagged++
+			}
+		}
+	}
+	if len(shallowest) == 1 {
+		return shallowest[0], true
+	}
+	if tagged == 1 {
+		for _, field := range shallowest {
+			if field.tagged {
+				return field, true
+			}
+		}
+	}
+	return jsonField{}, false
+}
+
+// jsonFieldName json 태그에서 필드 이름과 omitempty 여부를 읽습니다.
+func jsonFieldName(field reflect.StructField) (name string, omitempty bool, skip bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+
+	parts := strings.Split(tag, ",")
+	name = parts[0]
+	if name == "" {
+		name = field.Name
+	}
+	for _, option := range parts[1:] {
+		if option == "omitempty" {
+			omitempty = true
+		}
+	}
+	return name, omitempty, false
+}
@@ -0,0 +1,214 @@
+package tools
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testAudit struct {
+	CreatedBy string `json:"created_by"`
+	Note      string `json:"note,omitempty"`
+}
+
+type TestLocation struct {
+	City string `json:"city" description:"city name"`
+	Note string `json:"note"`
+}
+
+type testEventArgs struct {
+	testAudit               // 필드가 올라옴 (note 는 TestLocation 과 깊이가 같아 충돌)
+	*TestLocation           // 포인터 임베드도 올라옴
+	Title         string    `json:"title"`
+	At            time.Time `json:"at"`
+	Attachment    []byte    `json:"attachment,omitempty"`
+	Meta          testAudit `json:"meta,omitempty"` // 이름을 지정한 임베드가 아닌 필드
+}
+
+func TestGenerateSchemaMatchesEncodingJSON(t *testing.T) {
+	schema, err := GenerateSchema(testEventArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// encoding/json 이 쓰는 키와 스키마 속성이 같아야 합니다.
+	data, err := json.Marshal(testEventArgs{TestLocation: &TestLocation{}, Attachment: []byte{1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var encoded map[string]interface{}
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for key := range encoded {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	properties := append([]string(nil), schema.propertyNames()...)
+	sort.Strings(properties)
+	if !reflect.DeepEqual(properties, keys) {
+		t.Errorf("properties = %v, encoding/json keys = %v", properties, keys)
+	}
+
+	if want := []string{"created_by", "city", "title", "at", "attachment", "meta"}; !reflect.DeepEqual(schema.order, want) {
+		t.Errorf("order = %v, want %v", schema.order, want)
+	}
+	if got := schema.Properties["city"].Description; got != "city name" {
+		t.Errorf("city description = %q", got)
+	}
+	if got := schema.Properties["at"]; got.Type != "string" || got.Format != "date-time" {
+		t.Errorf("at = %+v, want date-time string", got)
+	}
+	if got := schema.Properties["attachment"]; got.Type != "string" || got.ContentEncoding != "base64" {
+		t.Errorf("attachment = %+v, want base64 string", got)
+	}
+}
+
+func TestSchemaDecode(t *testing.T) {
+	schema, err := GenerateSchema(testEventArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var args testEventArgs
+	valid := `{"created_by": "me", "city": "Seoul", "title": "launch", "at": "2024-10-01T09:30:00+09:00", "attachment": "aGk=", "meta": {"created_by": "bot"}}`
+	if err := schema.Decode(valid, &args); err != nil {
+		t.Fatalf("Decode() = %v", err)
+	}
+	if args.CreatedBy != "me" || args.TestLocation == nil || args.City != "Seoul" || string(args.Attachment) != "hi" || args.At.Hour() != 9 {
+		t.Errorf("decoded %+v", args)
+	}
+
+	invalid := `{"created_by": "me", "city": "Seoul", "title": "launch", "at": "tomorrow", "attachment": "not base64!", "meta": {"created_by": "bot"}}`
+	err = schema.Decode(invalid, &args)
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || len(validationErr.Problems) != 2 {
+		t.Fatalf("Decode() = %v, want two problems", err)
+	}
+	if !strings.HasPrefix(validationErr.Problems[0], "at:") || !strings.HasPrefix(validationErr.Problems[1], "attachment:") {
+		t.Errorf("problems = %v", validationErr.Problems)
+	}
+}
+
+func TestGenerateSchemaRejectsUnexportedEmbeddedPointer(t *testing.T) {
+	type args struct {
+		*testAudit
+	}
+	if _, err := GenerateSchema(args{}); err == nil {
+		t.Error("GenerateSchema() = nil error, want unsupported embedded pointer")
+	}
+}
+
+type testTaggedArgs struct {
+	Level    string           `json:"level" enum:"low, high" description:"priority"`
+	Count    int              `json:"count,omitempty" enum:"1,2,3"`
+	Ratio    float64          `json:"ratio" minimum:"0" maximum:"1.5"`
+	Name     *string          `json:"name,omitempty" minLength:"1" maxLength:"5" required:"true"`
+	Tags     []string         `json:"tags" minItems:"1" maxItems:"3" required:"false"`
+	Grid     [][]int          `json:"grid,omitempty"`
+	Scores   map[string]int   `json:"scores,omitempty"`
+	Children []testAudit      `json:"children,omitempty"`
+	Labels   map[string][]int `json:"labels,omitempty"`
+	Ignored  string           `json:"-"`
+	Plain    bool
+	hidden   string
+}
+
+func TestGenerateSchemaTags(t *testing.T) {
+	schema, err := GenerateSchema(&testTaggedArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"level", "ratio", "name", "Plain"}; !reflect.DeepEqual(schema.Required, want) {
+		t.Errorf("required = %v, want %v", schema.Required, want)
+	}
+
+	tests := []struct {
+		property, want string
+	}{
+		{"level", `{"type":"string","description":"priority","enum":["low","high"]}`},
+		{"count", `{"type":"integer","enum":[1,2,3]}`},
+		{"ratio", `{"type":"number","minimum":0,"maximum":1.5}`},
+		{"name", `{"type":"string","minLength":1,"maxLength":5}`},
+		{"tags", `{"type":"array","items":{"type":"string"},"minItems":1,"maxItems":3}`},
+		{"grid", `{"type":"array","items":{"type":"array","items":{"type":"integer"}}}`},
+		{"scores", `{"type":"object","additionalProperties":{"type":"integer"}}`},
+		{"children", `{"type":"array","items":{"type":"object","properties":{"created_by":{"type":"string"},"note":{"type":"string"}},"required":["created_by"]}}`},
+		{"labels", `{"type":"object","additionalProperties":{"type":"array","items":{"type":"integer"}}}`},
+		{"Plain", `{"type":"boolean"}`},
+	}
+	for _, tt := range tests {
+		property, ok := schema.Properties[tt.property]
+		if !ok {
+			t.Errorf("missing property %s", tt.property)
+			continue
+		}
+		data, err := json.Marshal(property)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != tt.want {
+			t.Errorf("%s = %s, want %s", tt.property, data, tt.want)
+		}
+	}
+	if len(schema.Properties) != len(tests) {
+		t.Errorf("properties = %v, want only %d properties", schema.propertyNames(), len(tests))
+	}
+}
+
+func TestGenerateSchemaErrors(t *testing.T) {
+	type recursive struct {
+		Next *recursive `json:"next"`
+	}
+
+	tests := []struct {
+		name    string
+		args    interface{}
+		wantErr string
+	}{
+		{"not a struct", 42, "arguments type must be a struct, got int"},
+		{"nil", nil, "arguments type must be a struct"},
+		{"minimum on string", struct {
+			A string `minimum:"1"`
+		}{}, "field A: minimum tag is only allowed on numeric fields"},
+		{"minLength on int", struct {
+			A int `minLength:"1"`
+		}{}, "field A: minLength tag is only allowed on string fields"},
+		{"minItems on string", struct {
+			A string `minItems:"1"`
+		}{}, "field A: minItems tag is only allowed on array fields"},
+		{"negative maxLength", struct {
+			A string `maxLength:"-1"`
+		}{}, `field A: invalid maxLength tag "-1"`},
+		{"bad integer enum", struct {
+			A int `enum:"1,two"`
+		}{}, `field A: invalid integer enum value "two"`},
+		{"enum on bool", struct {
+			A bool `enum:"true"`
+		}{}, "field A: enum tag is not supported on boolean fields"},
+		{"bad required tag", struct {
+			A string `required:"maybe"`
+		}{}, `field A: invalid required tag "maybe"`},
+		{"map with int keys", struct {
+			A map[int]string
+		}{}, "field A: map key type int is not supported"},
+		{"channel", struct {
+			A chan int
+		}{}, "field A: unsupported type chan int"},
+		{"recursive", recursive{}, "recursive type"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := GenerateSchema(tt.args)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("GenerateSchema() = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
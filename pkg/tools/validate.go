@@ -0,0 +1,242 @@
+package tools
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ValidationError 모델이 보낸 인자가 스키마와 맞지 않을 때 반환됩니다.
+// 메시지는 모델에게 그대로 돌려주어 인자를 고쳐 다시 호출할 수 있도록 작성됩니다.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid arguments: " + strings.Join(e.Problems, "; ")
+}
+
+// Decode arguments(JSON 문자열)를 스키마로 검증한 뒤 out 으로 디코딩합니다.
+func (s *Schema) Decode(arguments string, out interface{}) error {
+	if strings.TrimSpace(arguments) == "" {
+		arguments = "{}"
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(arguments))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return &ValidationError{Problems: []string{fmt.Sprintf("arguments are not valid JSON: %v", err)}}
+	}
+
+	if err := s.Validate(value); err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal([]byte(arguments), out); err != nil {
+		return &ValidationError{Problems: []string{err.Error()}}
+	}
+	return nil
+}
+
+// Validate json.Decoder(UseNumber)로 디코딩된 값을 스키마로 검증합니다.
+func (s *Schema) Validate(value interface{}) error {
+	var problems []string
+	s.validate("", value, &problems)
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+func (s *Schema) validate(path string, value interface{}, problems *[]string) {
+	report := func(format string, args ...interface{}) {
+		where := path
+		if where == "" {
+			where = "arguments"
+		}
+		*problems = append(*problems, where+": "+fmt.Sprintf(format, args...))
+	}
+
+	if value == nil {
+		report("must not be null")
+		return
+	}
+
+	switch s.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			report("expected object, got %s", jsonType(value))
+			return
+		}
+		required := make(map[string]bool, len(s.Required))
+		for _, name := range s.Required {
+			required[name] = true
+			if _, ok := object[name]; !ok {
+				report("missing required property %q", name)
+			}
+		}
+		for _, name := range s.propertyNames() {
+			v, ok := object[name]
+			if !ok || (v == nil && !required[name]) {
+				continue // 선택 속성의 null 은 생략과 같이 취급
+			}
+			s.Properties[name].validate(joinPath(path, name), v, problems)
+		}
+		if s.AdditionalProperties != nil {
+			keys := make([]string, 0, len(object))
+			for key := range object {
+				if _, declared := s.Properties[key]; !declared {
+					keys = append(keys, key)
+				}
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				s.AdditionalProperties.validate(joinPath(path, key), object[key], problems)
+			}
+		}
+	case "array":
+		array, ok := value.([]interface{})
+		if !ok {
+			report("expected array, got %s", jsonType(value))
+			return
+		}
+		if s.MinItems != nil && len(array) < *s.MinItems {
+			report("must contain at least %d items, got %d", *s.MinItems, len(array))
+		}
+		if s.MaxItems != nil && len(array) > *s.MaxItems {
+			report("must contain at most %d items, got %d", *s.MaxItems, len(array))
+		}
+		if s.Items != nil {
+			for i, item := range array {
+				s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, problems)
+			}
+		}
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			report("expected string, got %s", jsonType(value))
+			return
+		}
+		length := utf8.RuneCountInString(str)
+		if s.MinLength != nil && length < *s.MinLength {
+			report("must be at least %d characters long", *s.MinLength)
+		}
+		if s.MaxLength != nil && length > *s.MaxLength {
+			report("must be at most %d characters long", *s.MaxLength)
+		}
+		switch {
+		case s.Format == "date-time":
+			if _, err := time.Parse(time.RFC3339, str); err != nil {
+				report("must be an RFC 3339 date-time (e.g. 2024-10-01T09:30:00Z), got %q", str)
+			}
+		case s.ContentEncoding == "base64":
+			if _, err := base64.StdEncoding.DecodeString(str); err != nil {
+				report("must be base64 encoded")
+			}
+		}
+		s.validateEnum(str, report)
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			report("expected boolean, got %s", jsonType(value))
+		}
+	case "integer", "number":
+		number, ok := value.(json.Number)
+		if !ok {
+			report("expected %s, got %s", s.Type, jsonType(value))
+			return
+		}
+		f, err := number.Float64()
+		if err != nil {
+			report("invalid number %s", number)
+			return
+		}
+		if s.Type == "integer" && f != math.Trunc(f) {
+			report("expected integer, got %s", number)
+			return
+		}
+		if s.Minimum != nil && f < *s.Minimum {
+			report("must be >= %v, got %s", *s.Minimum, number)
+		}
+		if s.Maximum != nil && f > *s.Maximum {
+			report("must be <= %v, got %s", *s.Maximum, number)
+		}
+		s.validateEnum(f, report)
+	}
+}
+
+func (s *Schema) validateEnum(value interface{}, report func(format string, args ...interface{})) {
+	if len(s.Enum) == 0 {
+		return
+	}
+
+	for _, allowed := range s.Enum {
+		switch a := allowed.(type) {
+		case string:
+			if value == a {
+				return
+			}
+		case int64:
+			if value == float64(a) {
+				return
+			}
+		case float64:
+			if value == a {
+				return
+			}
+		}
+	}
+
+	allowed := make([]string, len(s.Enum))
+	for i, v := range s.Enum {
+		allowed[i] = fmt.Sprint(v)
+	}
+	report("must be one of [%s], got %v", strings.Join(allowed, ", "), value)
+}
+
+// propertyNames 선언 순서대로 속성 이름을 반환합니다.
+func (s *Schema) propertyNames() []string {
+	if len(s.order) == len(s.Properties) {
+		return s.order
+	}
+
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case json.Number, float64:
+		return "number"
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
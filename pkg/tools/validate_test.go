@@ -0,0 +1,79 @@
+package tools
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSchemaValidate(t *testing.T) {
+	schema, err := GenerateSchema(testTaggedArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	valid := `{"level": "low", "ratio": 0.5, "name": "Min", "tags": ["a"], "Plain": true}`
+	tests := []struct {
+		name      string
+		arguments string
+		want      []string // 비어 있으면 통과
+	}{
+		{"valid", valid, nil},
+		{"optional null", `{"level": "low", "ratio": 1, "name": "Min", "Plain": false, "count": null, "tags": null}`, nil},
+		{"empty arguments", ``, []string{
+			`arguments: missing required property "level"`,
+			`arguments: missing required property "ratio"`,
+			`arguments: missing required property "name"`,
+			`arguments: missing required property "Plain"`,
+		}},
+		{"not json", `{"level":`, []string{"arguments are not valid JSON: unexpected EOF"}},
+		{"not an object", `[1]`, []string{"arguments: expected object, got array"}},
+		{"enum", `{"level": "urgent", "ratio": 0, "name": "Min", "Plain": true, "count": 4}`, []string{
+			"level: must be one of [low, high], got urgent",
+			"count: must be one of [1, 2, 3], got 4",
+		}},
+		{"range", `{"level": "low", "ratio": 1.6, "name": "Min", "Plain": true}`, []string{"ratio: must be <= 1.5, got 1.6"}},
+		{"below minimum", `{"level": "low", "ratio": -1, "name": "Min", "Plain": true}`, []string{"ratio: must be >= 0, got -1"}},
+		{"length", `{"level": "low", "ratio": 0, "name": "", "Plain": true}`, []string{"name: must be at least 1 characters long"}},
+		{"max length counts runes", `{"level": "low", "ratio": 0, "name": "민준이에요", "Plain": true}`, nil},
+		{"too long", `{"level": "low", "ratio": 0, "name": "abcdef", "Plain": true}`, []string{"name: must be at most 5 characters long"}},
+		{"required null", `{"level": "low", "ratio": 0, "name": null, "Plain": true}`, []string{"name: must not be null"}},
+		{"items", `{"level": "low", "ratio": 0, "name": "Min", "Plain": true, "tags": []}`, []string{"tags: must contain at least 1 items, got 0"}},
+		{"max items", `{"level": "low", "ratio": 0, "name": "Min", "Plain": true, "tags": ["a", "b", "c", "d"]}`, []string{"tags: must contain at most 3 items, got 4"}},
+		{"types", `{"level": 1, "ratio": "high", "name": "Min", "Plain": "yes", "count": 1.5}`, []string{
+			"level: expected string, got number",
+			"count: expected integer, got 1.5",
+			"ratio: expected number, got string",
+			"Plain: expected boolean, got string",
+		}},
+		{"nested arrays", `{"level": "low", "ratio": 0, "name": "Min", "Plain": true, "grid": [[1, 2], [3, "x"]]}`, []string{"grid[1][1]: expected integer, got string"}},
+		{"nested objects", `{"level": "low", "ratio": 0, "name": "Min", "Plain": true, "children": [{"note": "x"}]}`, []string{`children[0]: missing required property "created_by"`}},
+		{"map values", `{"level": "low", "ratio": 0, "name": "Min", "Plain": true, "scores": {"b": "x", "a": 1}, "labels": {"z": [1, true]}}`, []string{
+			"scores.b: expected integer, got string",
+			"labels.z[1]: expected integer, got boolean",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var args testTaggedArgs
+			err := schema.Decode(tt.arguments, &args)
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Errorf("Decode() = %v, want nil", err)
+				}
+				return
+			}
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Decode() = %v, want *ValidationError", err)
+			}
+			if !reflect.DeepEqual(validationErr.Problems, tt.want) {
+				t.Errorf("problems =\n  %s\nwant\n  %s", strings.Join(validationErr.Problems, "\n  "), strings.Join(tt.want, "\n  "))
+			}
+			if want := "invalid arguments: " + strings.Join(tt.want, "; "); err.Error() != want {
+				t.Errorf("Error() = %q, want %q", err, want)
+			}
+		})
+	}
+}
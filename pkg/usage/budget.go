@@ -0,0 +1,144 @@
+package usage
+
+import (
+	"context"
+	"fmt"
+	"openai-realtime/pkg/openai"
+	"openai-realtime/pkg/openai/events"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Limit 세션 사용량 한도 (0 인 항목은 확인하지 않음)
+type Limit struct {
+	Tokens  int
+	CostUSD float64
+}
+
+// ParseLimit "20000" (토큰) 또는 "$0.50" (예상 비용, USD) 형식의 한도를 읽습니다. 빈 문자열은 한도 없음입니다.
+func ParseLimit(s string) (Limit, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Limit{}, nil
+	}
+	if dollars, ok := strings.CutPrefix(s, "$"); ok {
+		cost, err := strconv.ParseFloat(dollars, 64)
+		if err != nil || cost <= 0 {
+			return Limit{}, fmt.Errorf("invalid cost limit %q", s)
+		}
+		return Limit{CostUSD: cost}, nil
+	}
+	tokens, err := strconv.Atoi(s)
+	if err != nil || tokens <= 0 {
+		return Limit{}, fmt.Errorf("invalid token limit %q (want tokens like 20000 or dollars like $0.50)", s)
+	}
+	return Limit{Tokens: tokens}, nil
+}
+
+// IsZero 한도가 설정되지 않았는지 여부
+func (l Limit) IsZero() bool {
+	return l.Tokens == 0 && l.CostUSD == 0
+}
+
+// reached used 가 한도에 도달했는지 여부
+func (l Limit) reached(used Summary) bool {
+	return (l.Tokens > 0 && used.Tokens.Total >= l.Tokens) ||
+		(l.CostUSD > 0 && used.CostUSD >= l.CostUSD)
+}
+
+func (l Limit) String() string {
+	switch {
+	case l.Tokens > 0 && l.CostUSD > 0:
+		return fmt.Sprintf("%d tokens or $%g", l.Tokens, l.CostUSD)
+	case l.CostUSD > 0:
+		return fmt.Sprintf("$%g", l.CostUSD)
+	case l.Tokens > 0:
+		return fmt.Sprintf("%d tokens", l.Tokens)
+	}
+	return "unlimited"
+}
+
+// Budget 세션 예산
+// 소프트 한도에 도달하면 SoftOptions 로 세션을 낮추고, 하드 한도에 도달하면 세션 종료를 알립니다.
+type Budget struct {
+	Soft        Limit
+	Hard        Limit
+	SoftOptions []openai.SessionOption // 소프트 한도에서 덧붙일 세션 옵션 (예: 텍스트 전용, 짧은 응답)
+	WrapUp      string                 // 소프트 한도 이후 어시스턴트가 대화를 마무리하도록 지시문에 덧붙일 내용 (비어 있으면 사용 안 함)
+}
+
+// 소프트 한도에서 적용할 수 있는 기본 옵션
+var (
+	TextOnlyOptions      = []openai.SessionOption{openai.WithModalities("text")}
+	ShortResponseOptions = []openai.SessionOption{openai.WithMaxResponseOutputTokens(256)}
+)
+
+// DefaultWrapUp 소프트 한도 이후 대화를 마무리하도록 하는 지시
+const DefaultWrapUp = "The session is almost over. Keep answers very short and gently wrap up the conversation, saying goodbye within the next reply or two."
+
+// Guard 세션 사용량을 예산과 비교해 세션을 낮추거나 끝냅니다.
+type Guard struct {
+	budget Budget
+	prices Prices
+
+	mu      sync.Mutex
+	used    Summary
+	softHit bool
+	hardHit bool
+}
+
+// NewGuard prices 로 비용을 계산하는 예산 감시자를 만듭니다.
+func NewGuard(budget Budget, prices Prices) *Guard {
+	return &Guard{budget: budget, prices: prices}
+}
+
+// Attach 클라이언트의 응답 사용량을 누적합니다. 재연결로 세션이 바뀌어도 계속 누적합니다.
+// 하드 한도에 도달하면 onHard 를 한 번 호출하며, last 는 한도를 넘긴 응답입니다.
+// onHard 는 수신 goroutine 에서 호출되므로 오래 걸리는 작업은 별도 goroutine 에서 처리해야 합니다.
+func (g *Guard) Attach(c *openai.Client, onHard func(used Summary, last events.Response)) (detach func()) {
+	return openai.On(c, events.ResponseDoneEventType, func(ctx context.Context, e events.ResponseDone) {
+		tokens := tokensFrom(e.Response.Usage)
+
+		g.mu.Lock()
+		g.used.add(Record{Tokens: tokens, CostUSD: g.prices.Cost(tokens)})
+		used := g.used
+		soft := !g.softHit && g.budget.Soft.reached(used)
+		hard := !g.hardHit && g.budget.Hard.reached(used)
+		g.softHit = g.softHit || soft
+		g.hardHit = g.hardHit || hard
+		g.mu.Unlock()
+
+		if soft {
+			log.Warnf("Soft budget %s reached (used %d tokens, $%.4f), degrading session", g.budget.Soft, used.Tokens.Total, used.CostUSD)
+			if err := c.AmendSession(g.softOptions()...); err != nil {
+				log.Errorf("Failed to degrade session: %v", err)
+			}
+		}
+		if hard {
+			log.Warnf("Hard budget %s reached (used %d tokens, $%.4f), ending session", g.budget.Hard, used.Tokens.Total, used.CostUSD)
+			if onHard != nil {
+				onHard(used, e.Response)
+			}
+		}
+	})
+}
+
+// softOptions 소프트 한도에서 덧붙일 세션 옵션
+func (g *Guard) softOptions() []openai.SessionOption {
+	opts := append([]openai.SessionOption(nil), g.budget.SoftOptions...)
+	if g.budget.WrapUp != "" {
+		wrapUp := g.budget.WrapUp
+		opts = append(opts, func(config *openai.SessionConfig) {
+			config.Instructions = strings.TrimSpace(config.Instructions + "\n\n" + wrapUp)
+		})
+	}
+	return opts
+}
+
+// Used 지금까지의 사용량
+func (g *Guard) Used() Summary {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.used
+}
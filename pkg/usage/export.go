@@ -0,0 +1,121 @@
+package usage
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// report JSON 으로 내보내는 장부 전체
+type report struct {
+	Records   []Record           `json:"records"`
+	Total     Summary            `json:"total"`
+	BySession map[string]Summary `json:"by_session"`
+	ByPersona map[string]Summary `json:"by_persona"`
+	Prices    Prices             `json:"prices"`
+}
+
+var csvHeader = []string{
+	"time", "session_id", "persona", "response_id", "status",
+	"input_text", "input_audio", "cached_input_text", "cached_input_audio",
+	"output_text", "output_audio", "total_tokens", "cost_usd",
+}
+
+// WriteJSON 응답별 기록과 세션, 페르소나별 합계를 JSON 으로 씁니다.
+func (l *Ledger) WriteJSON(w io.Writer) error {
+	records := l.Records()
+	if records == nil {
+		records = []Record{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report{
+		Records:   records,
+		Total:     l.Total(),
+		BySession: l.BySession(),
+		ByPersona: l.ByPersona(),
+		Prices:    l.prices,
+	})
+}
+
+// WriteCSV 응답별 기록을 CSV 로 씁니다. header 가 true 면 첫 줄에 열 이름을 씁니다.
+func (l *Ledger) WriteCSV(w io.Writer, header bool) error {
+	writer := csv.NewWriter(w)
+	if header {
+		if err := writer.Write(csvHeader); err != nil {
+			return err
+		}
+	}
+	for _, r := range l.Records() {
+		t := r.Tokens
+		row := []string{
+			r.Time.Format(time.RFC3339), r.SessionID, r.Persona, r.ResponseID, r.Status,
+			strconv.Itoa(t.InputText), strconv.Itoa(t.InputAudio), strconv.Itoa(t.CachedInputText), strconv.Itoa(t.CachedInputAudio),
+			strconv.Itoa(t.OutputText), strconv.Itoa(t.OutputAudio), strconv.Itoa(t.Total),
+			strconv.FormatFloat(r.CostUSD, 'f', 6, 64),
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// Export 확장자에 따라 path 에 내보냅니다.
+// .csv 는 기존 파일 뒤에 이어 써서 여러 실행의 기록을 모을 수 있고, .json 은 이번 실행의 장부로 덮어씁니다.
+func (l *Ledger) Export(path string) error {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		_, err := os.Stat(path)
+		header := errors.Is(err, fs.ErrNotExist)
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return fmt.Errorf("failed to open usage file: %w", err)
+		}
+		defer f.Close()
+		return l.WriteCSV(f, header)
+	case ".json":
+		f, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("failed to create usage file: %w", err)
+		}
+		defer f.Close()
+		return l.WriteJSON(f)
+	default:
+		return fmt.Errorf("unsupported usage file format %q (want .json or .csv)", filepath.Ext(path))
+	}
+}
+
+// PrintSummary 전체, 세션별, 페르소나별 사용량과 예상 비용을 씁니다.
+func (l *Ledger) PrintSummary(w io.Writer) {
+	total := l.Total()
+	fmt.Fprintf(w, "Usage: %d responses, %d tokens, $%.4f\n", total.Responses, total.Tokens.Total, total.CostUSD)
+	if total.Responses == 0 {
+		return
+	}
+	t := total.Tokens
+	fmt.Fprintf(w, "  input  text %d (cached %d), audio %d (cached %d)\n", t.InputText, t.CachedInputText, t.InputAudio, t.CachedInputAudio)
+	fmt.Fprintf(w, "  output text %d, audio %d\n", t.OutputText, t.OutputAudio)
+
+	for _, group := range []struct {
+		name      string
+		summaries map[string]Summary
+	}{
+		{"session", l.BySession()},
+		{"persona", l.ByPersona()},
+	} {
+		for _, key := range sortedKeys(group.summaries) {
+			s := group.summaries[key]
+			fmt.Fprintf(w, "  %s %s: %d responses, %d tokens, $%.4f\n", group.name, key, s.Responses, s.Tokens.Total, s.CostUSD)
+		}
+	}
+}
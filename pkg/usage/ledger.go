@@ -0,0 +1,186 @@
+package usage
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"openai-realtime/pkg/config"
+	"openai-realtime/pkg/openai"
+	"openai-realtime/pkg/openai/events"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+var log = func() *logrus.Logger {
+	log := logrus.New()
+	log.SetOutput(os.Stdout)
+	log.SetLevel(config.LogLevel)
+	return log
+}()
+
+// Tokens 토큰 사용량 (입력 토큰은 캐시 적중분을 제외한 값)
+type Tokens struct {
+	InputText        int `json:"input_text"`
+	InputAudio       int `json:"input_audio"`
+	CachedInputText  int `json:"cached_input_text"`
+	CachedInputAudio int `json:"cached_input_audio"`
+	OutputText       int `json:"output_text"`
+	OutputAudio      int `json:"output_audio"`
+	Total            int `json:"total"`
+}
+
+func (t *Tokens) add(o Tokens) {
+	t.InputText += o.InputText
+	t.InputAudio += o.InputAudio
+	t.CachedInputText += o.CachedInputText
+	t.CachedInputAudio += o.CachedInputAudio
+	t.OutputText += o.OutputText
+	t.OutputAudio += o.OutputAudio
+	t.Total += o.Total
+}
+
+// tokensFrom response.done 의 usage 를 Tokens 로 변환합니다.
+func tokensFrom(u events.Usage) Tokens {
+	cached := u.InputTokenDetails.CachedTokensDetails
+	return Tokens{
+		InputText:        u.InputTokenDetails.TextTokens - cached.TextTokens,
+		InputAudio:       u.InputTokenDetails.AudioTokens - cached.AudioTokens,
+		CachedInputText:  cached.TextTokens,
+		CachedInputAudio: cached.AudioTokens,
+		OutputText:       u.OutputTokenDetails.TextTokens,
+		OutputAudio:      u.OutputTokenDetails.AudioTokens,
+		Total:            u.TotalTokens,
+	}
+}
+
+// Record 응답 하나의 사용량
+type Record struct {
+	Time       time.Time `json:"time"`
+	SessionID  string    `json:"session_id"`
+	Persona    string    `json:"persona"`
+	ResponseID string    `json:"response_id"`
+	Status     string    `json:"status"`
+	Tokens     Tokens    `json:"tokens"`
+	CostUSD    float64   `json:"cost_usd"`
+}
+
+// Summary 여러 응답의 합계
+type Summary struct {
+	Responses int     `json:"responses"`
+	Tokens    Tokens  `json:"tokens"`
+	CostUSD   float64 `json:"cost_usd"`
+}
+
+func (s *Summary) add(r Record) {
+	s.Responses++
+	s.Tokens.add(r.Tokens)
+	s.CostUSD += r.CostUSD
+}
+
+// Ledger response.done 의 사용량을 모아 응답, 세션, 페르소나별로 집계합니다.
+type Ledger struct {
+	mu      sync.Mutex
+	prices  Prices
+	records []Record
+	now     func() time.Time
+}
+
+// NewLedger prices 로 비용을 계산하는 빈 장부를 만듭니다.
+func NewLedger(prices Prices) *Ledger {
+	return &Ledger{prices: prices, now: time.Now}
+}
+
+// Attach 클라이언트의 응답 사용량을 persona 이름으로 기록합니다.
+// 재연결로 세션이 바뀌면 새 세션 ID 로 기록합니다. 반환된 함수를 호출하면 기록을 멈춥니다.
+func (l *Ledger) Attach(c *openai.Client, persona string) (detach func()) {
+	var mu sync.Mutex
+	sessionID := ""
+
+	unsubscribes := []func(){
+		openai.On(c, events.SessionCreatedEventType, func(ctx context.Context, e events.SessionCreatedEvent) {
+			mu.Lock()
+			defer mu.Unlock()
+			sessionID = e.Session.ID
+		}),
+		openai.On(c, events.ResponseDoneEventType, func(ctx context.Context, e events.ResponseDone) {
+			mu.Lock()
+			id := sessionID
+			mu.Unlock()
+			l.Add(id, persona, e.Response)
+		}),
+	}
+
+	return func() {
+		for _, unsubscribe := range unsubscribes {
+			unsubscribe()
+		}
+	}
+}
+
+// Add 응답 하나의 사용량을 기록합니다.
+func (l *Ledger) Add(sessionID, persona string, response events.Response) Record {
+	tokens := tokensFrom(response.Usage)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	record := Record{
+		Time:       l.now(),
+		SessionID:  sessionID,
+		Persona:    persona,
+		ResponseID: response.ID,
+		Status:     response.Status,
+		Tokens:     tokens,
+		CostUSD:    l.prices.Cost(tokens),
+	}
+	l.records = append(l.records, record)
+	log.Debugf("Usage for %s: %d tokens, $%.4f", response.ID, tokens.Total, record.CostUSD)
+	return record
+}
+
+// Records 기록된 응답별 사용량 (기록 순)
+func (l *Ledger) Records() []Record {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]Record(nil), l.records...)
+}
+
+// Total 전체 합계
+func (l *Ledger) Total() Summary {
+	var total Summary
+	for _, r := range l.Records() {
+		total.add(r)
+	}
+	return total
+}
+
+// BySession 세션별 합계
+func (l *Ledger) BySession() map[string]Summary {
+	return l.group(func(r Record) string { return r.SessionID })
+}
+
+// ByPersona 페르소나별 합계
+func (l *Ledger) ByPersona() map[string]Summary {
+	return l.group(func(r Record) string { return r.Persona })
+}
+
+func (l *Ledger) group(key func(Record) string) map[string]Summary {
+	groups := make(map[string]Summary)
+	for _, r := range l.Records() {
+		s := groups[key(r)]
+		s.add(r)
+		groups[key(r)] = s
+	}
+	return groups
+}
+
+// sortedKeys 이름순 키
+func sortedKeys(m map[string]Summary) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
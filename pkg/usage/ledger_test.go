@@ -0,0 +1,104 @@
+package usage
+
+import (
+	"encoding/json"
+	"math"
+	"openai-realtime/pkg/openai/events"
+	"testing"
+)
+
+// testResponse usage 가 message 인 response.done 응답
+func testResponse(t *testing.T, id, usage string) events.Response {
+	t.Helper()
+
+	var response events.Response
+	response.ID = id
+	response.Status = "completed"
+	if err := json.Unmarshal([]byte(usage), &response.Usage); err != nil {
+		t.Fatal(err)
+	}
+	return response
+}
+
+func TestTokensFrom(t *testing.T) {
+	response := testResponse(t, "resp_1", `{
+		"total_tokens": 1600,
+		"input_token_details": {"text_tokens": 300, "audio_tokens": 500, "cached_tokens_details": {"text_tokens": 100, "audio_tokens": 200}},
+		"output_token_details": {"text_tokens": 200, "audio_tokens": 600}
+	}`)
+
+	want := Tokens{InputText: 200, InputAudio: 300, CachedInputText: 100, CachedInputAudio: 200, OutputText: 200, OutputAudio: 600, Total: 1600}
+	if got := tokensFrom(response.Usage); got != want {
+		t.Errorf("tokensFrom() = %+v, want %+v", got, want)
+	}
+}
+
+func TestLedger(t *testing.T) {
+	prices, err := PricesFor("gpt-4o-realtime-preview-2024-10-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := NewLedger(prices)
+
+	audio := `{"total_tokens": 2000, "input_token_details": {"audio_tokens": 1000}, "output_token_details": {"audio_tokens": 1000}}`
+	text := `{"total_tokens": 1000, "input_token_details": {"text_tokens": 500}, "output_token_details": {"text_tokens": 500}}`
+	l.Add("sess_1", "tutor", testResponse(t, "resp_1", audio))
+	l.Add("sess_1", "tutor", testResponse(t, "resp_2", text))
+	l.Add("sess_2", "guide", testResponse(t, "resp_3", text))
+
+	total := l.Total()
+	if total.Responses != 3 || total.Tokens.Total != 4000 {
+		t.Errorf("total = %+v, want 3 responses and 4000 tokens", total)
+	}
+	// 음성 1000 입력 $100/1M + 1000 출력 $200/1M, 텍스트 500 입력 $5/1M + 500 출력 $20/1M (두 번)
+	wantCost := 0.1 + 0.2 + 2*(0.0025+0.01)
+	if math.Abs(total.CostUSD-wantCost) > 1e-9 {
+		t.Errorf("total cost = %v, want %v", total.CostUSD, wantCost)
+	}
+
+	bySession := l.BySession()
+	if got := bySession["sess_1"].Responses; got != 2 {
+		t.Errorf("sess_1 responses = %d, want 2", got)
+	}
+	byPersona := l.ByPersona()
+	if got := byPersona["guide"].Tokens.Total; got != 1000 {
+		t.Errorf("guide tokens = %d, want 1000", got)
+	}
+	if keys := sortedKeys(byPersona); len(keys) != 2 || keys[0] != "guide" {
+		t.Errorf("sortedKeys() = %v, want [guide tutor]", keys)
+	}
+}
+
+func TestPricesFor(t *testing.T) {
+	prices, err := PricesFor("gpt-4o-realtime-preview-2024-10-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prices.InputAudio != 100 || prices.CachedInputAudio != 20 || prices.OutputAudio != 200 {
+		t.Errorf("2024-10-01 audio prices = %+v, want 100/20/200", prices)
+	}
+	if _, err := PricesFor("gpt-unknown"); err == nil {
+		t.Error("PricesFor(unknown model) returned no error")
+	}
+}
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Limit
+		wantErr bool
+	}{
+		{in: "", want: Limit{}},
+		{in: "20000", want: Limit{Tokens: 20000}},
+		{in: " $0.50 ", want: Limit{CostUSD: 0.5}},
+		{in: "$0", wantErr: true},
+		{in: "-5", wantErr: true},
+		{in: "lots", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseLimit(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseLimit(%q) = %+v, %v, want %+v (error %v)", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
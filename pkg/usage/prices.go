@@ -0,0 +1,78 @@
+package usage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Prices 토큰 1M 개당 가격 (USD)
+type Prices struct {
+	InputText        float64 `json:"input_text"`
+	InputAudio       float64 `json:"input_audio"`
+	CachedInputText  float64 `json:"cached_input_text"`
+	CachedInputAudio float64 `json:"cached_input_audio"`
+	OutputText       float64 `json:"output_text"`
+	OutputAudio      float64 `json:"output_audio"`
+}
+
+// ModelPrices 모델별 기본 가격 (스냅샷 이름 기준)
+var ModelPrices = map[string]Prices{
+	"gpt-4o-realtime-preview-2024-10-01": {
+		InputText:        5,
+		InputAudio:       100,
+		CachedInputText:  2.5,
+		CachedInputAudio: 20,
+		OutputText:       20,
+		OutputAudio:      200,
+	},
+	"gpt-4o-realtime-preview-2024-12-17": {
+		InputText:        5,
+		InputAudio:       40,
+		CachedInputText:  2.5,
+		CachedInputAudio: 2.5,
+		OutputText:       20,
+		OutputAudio:      80,
+	},
+	"gpt-4o-mini-realtime-preview-2024-12-17": {
+		InputText:        0.6,
+		InputAudio:       10,
+		CachedInputText:  0.3,
+		CachedInputAudio: 0.3,
+		OutputText:       2.4,
+		OutputAudio:      20,
+	},
+}
+
+// PricesFor model 의 기본 가격
+func PricesFor(model string) (Prices, error) {
+	prices, ok := ModelPrices[model]
+	if !ok {
+		return Prices{}, fmt.Errorf("no built-in prices for model %s", model)
+	}
+	return prices, nil
+}
+
+// LoadPrices JSON 가격표를 읽습니다. 파일에 없는 항목은 base 값을 사용합니다.
+func LoadPrices(path string, base Prices) (Prices, error) {
+	prices := base
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return prices, fmt.Errorf("failed to read price table: %w", err)
+	}
+	if err := json.Unmarshal(data, &prices); err != nil {
+		return prices, fmt.Errorf("invalid price table %s: %w", path, err)
+	}
+	return prices, nil
+}
+
+// Cost 사용량의 예상 비용 (USD)
+func (p Prices) Cost(t Tokens) float64 {
+	sum := float64(t.InputText)*p.InputText +
+		float64(t.InputAudio)*p.InputAudio +
+		float64(t.CachedInputText)*p.CachedInputText +
+		float64(t.CachedInputAudio)*p.CachedInputAudio +
+		float64(t.OutputText)*p.OutputText +
+		float64(t.OutputAudio)*p.OutputAudio
+	return sum / 1_000_000
+}